@@ -0,0 +1,2011 @@
+# Generated by generate/generator; do not edit.
+
+group "default" {
+  targets = [
+    "couchbase-server",
+    "sync-gateway",
+    "server-sandbox",
+    "couchbase-edge-server",
+    "enterprise-analytics",
+  ]
+}
+
+group "couchbase-server" {
+  targets = [
+    "couchbase-server-community",
+    "couchbase-server-enterprise",
+  ]
+}
+
+group "couchbase-server-community" {
+  targets = [
+    "couchbase-server-community-4_0_0",
+    "couchbase-server-community-4_1_0",
+    "couchbase-server-community-4_1_1",
+    "couchbase-server-community-4_5_0",
+    "couchbase-server-community-4_5_1",
+    "couchbase-server-community-5_0_1",
+    "couchbase-server-community-5_1_1",
+    "couchbase-server-community-6_0_0",
+    "couchbase-server-community-6_5_0",
+    "couchbase-server-community-6_5_1",
+    "couchbase-server-community-6_6_0",
+    "couchbase-server-community-7_0_0-beta",
+    "couchbase-server-community-7_0_0",
+    "couchbase-server-community-7_0_1",
+    "couchbase-server-community-7_0_2",
+    "couchbase-server-community-7_1_0",
+    "couchbase-server-community-7_1_1",
+    "couchbase-server-community-7_2_0",
+    "couchbase-server-community-7_2_2",
+    "couchbase-server-community-7_2_4",
+    "couchbase-server-community-7_6_0",
+    "couchbase-server-community-7_6_1",
+    "couchbase-server-community-7_6_2",
+    "couchbase-server-community-8_0_0",
+    "couchbase-server-community-8_0_1",
+  ]
+}
+
+group "couchbase-server-enterprise" {
+  targets = [
+    "couchbase-server-enterprise-4_0_0",
+    "couchbase-server-enterprise-4_1_0",
+    "couchbase-server-enterprise-4_1_1",
+    "couchbase-server-enterprise-4_1_2",
+    "couchbase-server-enterprise-4_5_0",
+    "couchbase-server-enterprise-4_5_1",
+    "couchbase-server-enterprise-4_6_0",
+    "couchbase-server-enterprise-4_6_1",
+    "couchbase-server-enterprise-4_6_2",
+    "couchbase-server-enterprise-4_6_3",
+    "couchbase-server-enterprise-4_6_4",
+    "couchbase-server-enterprise-4_6_5",
+    "couchbase-server-enterprise-5_0_1",
+    "couchbase-server-enterprise-5_1_0",
+    "couchbase-server-enterprise-5_1_1",
+    "couchbase-server-enterprise-5_1_2",
+    "couchbase-server-enterprise-5_1_3",
+    "couchbase-server-enterprise-5_5_0",
+    "couchbase-server-enterprise-5_5_1",
+    "couchbase-server-enterprise-5_5_2",
+    "couchbase-server-enterprise-5_5_3",
+    "couchbase-server-enterprise-5_5_4",
+    "couchbase-server-enterprise-5_5_5",
+    "couchbase-server-enterprise-5_5_6",
+    "couchbase-server-enterprise-6_0_0",
+    "couchbase-server-enterprise-6_0_1",
+    "couchbase-server-enterprise-6_0_2",
+    "couchbase-server-enterprise-6_0_3",
+    "couchbase-server-enterprise-6_0_4",
+    "couchbase-server-enterprise-6_0_5",
+    "couchbase-server-enterprise-6_5_0-beta",
+    "couchbase-server-enterprise-6_5_0-beta2",
+    "couchbase-server-enterprise-6_5_0",
+    "couchbase-server-enterprise-6_5_1",
+    "couchbase-server-enterprise-6_5_2",
+    "couchbase-server-enterprise-6_6_0",
+    "couchbase-server-enterprise-6_6_1",
+    "couchbase-server-enterprise-6_6_2",
+    "couchbase-server-enterprise-6_6_3",
+    "couchbase-server-enterprise-6_6_4",
+    "couchbase-server-enterprise-6_6_5",
+    "couchbase-server-enterprise-6_6_6",
+    "couchbase-server-enterprise-7_0_0-beta",
+    "couchbase-server-enterprise-7_0_0",
+    "couchbase-server-enterprise-7_0_0-5017",
+    "couchbase-server-enterprise-7_0_1",
+    "couchbase-server-enterprise-7_0_2",
+    "couchbase-server-enterprise-7_0_3",
+    "couchbase-server-enterprise-7_0_4",
+    "couchbase-server-enterprise-7_0_5",
+    "couchbase-server-enterprise-7_1_0",
+    "couchbase-server-enterprise-7_1_1",
+    "couchbase-server-enterprise-7_1_2",
+    "couchbase-server-enterprise-7_1_3",
+    "couchbase-server-enterprise-7_1_4",
+    "couchbase-server-enterprise-7_1_5",
+    "couchbase-server-enterprise-7_1_6",
+    "couchbase-server-enterprise-7_2_0",
+    "couchbase-server-enterprise-7_2_2",
+    "couchbase-server-enterprise-7_2_3",
+    "couchbase-server-enterprise-7_2_4",
+    "couchbase-server-enterprise-7_2_5",
+    "couchbase-server-enterprise-7_2_6",
+    "couchbase-server-enterprise-7_2_7",
+    "couchbase-server-enterprise-7_2_8",
+    "couchbase-server-enterprise-7_2_9",
+    "couchbase-server-enterprise-7_6_0",
+    "couchbase-server-enterprise-7_6_1",
+    "couchbase-server-enterprise-7_6_2",
+    "couchbase-server-enterprise-7_6_3",
+    "couchbase-server-enterprise-7_6_4",
+    "couchbase-server-enterprise-7_6_5",
+    "couchbase-server-enterprise-7_6_6",
+    "couchbase-server-enterprise-7_6_7",
+    "couchbase-server-enterprise-7_6_8",
+    "couchbase-server-enterprise-7_6_9",
+    "couchbase-server-enterprise-7_6_10",
+    "couchbase-server-enterprise-7_6_11",
+    "couchbase-server-enterprise-8_0_0",
+    "couchbase-server-enterprise-8_0_1",
+  ]
+}
+
+group "sync-gateway" {
+  targets = [
+    "sync-gateway-community",
+    "sync-gateway-enterprise",
+  ]
+}
+
+group "sync-gateway-community" {
+  targets = [
+    "sync-gateway-community-1_0_4",
+    "sync-gateway-community-1_1_0-forestdb_bucket",
+    "sync-gateway-community-1_1_0",
+    "sync-gateway-community-1_1_1",
+    "sync-gateway-community-1_2_0-rc0",
+    "sync-gateway-community-1_2_0-rc1",
+    "sync-gateway-community-1_2_0",
+    "sync-gateway-community-1_2_1",
+    "sync-gateway-community-1_3_0-274",
+    "sync-gateway-community-1_3_1-16",
+    "sync-gateway-community-1_4_0-2",
+    "sync-gateway-community-1_4_1-3",
+    "sync-gateway-community-1_5_0-377",
+    "sync-gateway-community-1_5_1",
+    "sync-gateway-community-2_0_0-devbuild",
+    "sync-gateway-community-2_0_0",
+    "sync-gateway-community-2_1_0",
+    "sync-gateway-community-2_1_1",
+    "sync-gateway-community-2_1_2",
+    "sync-gateway-community-2_1_3",
+    "sync-gateway-community-2_5_0",
+    "sync-gateway-community-2_5_1",
+    "sync-gateway-community-2_6_0",
+    "sync-gateway-community-2_6_1",
+    "sync-gateway-community-2_7_0",
+    "sync-gateway-community-2_7_1",
+    "sync-gateway-community-2_7_2",
+    "sync-gateway-community-2_7_3",
+    "sync-gateway-community-2_7_4",
+    "sync-gateway-community-2_8_0",
+    "sync-gateway-community-2_8_2",
+    "sync-gateway-community-2_8_3",
+    "sync-gateway-community-2_8_4",
+    "sync-gateway-community-3_0_3",
+    "sync-gateway-community-3_0_4",
+    "sync-gateway-community-3_0_5",
+    "sync-gateway-community-3_0_7",
+    "sync-gateway-community-3_0_8",
+    "sync-gateway-community-3_0_9",
+    "sync-gateway-community-3_1_0",
+    "sync-gateway-community-3_1_1",
+    "sync-gateway-community-3_1_2",
+    "sync-gateway-community-3_1_3",
+    "sync-gateway-community-3_1_5",
+    "sync-gateway-community-3_1_6",
+    "sync-gateway-community-3_1_7",
+    "sync-gateway-community-3_1_8",
+    "sync-gateway-community-3_1_9",
+    "sync-gateway-community-3_1_10",
+    "sync-gateway-community-3_1_11",
+    "sync-gateway-community-3_1_12",
+    "sync-gateway-community-3_2_0",
+    "sync-gateway-community-3_2_1",
+    "sync-gateway-community-3_2_2",
+    "sync-gateway-community-3_2_3",
+    "sync-gateway-community-3_2_4",
+    "sync-gateway-community-3_2_5",
+    "sync-gateway-community-3_2_6",
+    "sync-gateway-community-3_2_7",
+    "sync-gateway-community-3_3_0",
+    "sync-gateway-community-3_3_1",
+    "sync-gateway-community-3_3_2",
+    "sync-gateway-community-3_3_3",
+    "sync-gateway-community-3_3_4",
+    "sync-gateway-community-4_0_0",
+    "sync-gateway-community-4_0_1",
+    "sync-gateway-community-4_0_2",
+    "sync-gateway-community-4_0_3",
+    "sync-gateway-community-4_0_4",
+  ]
+}
+
+group "sync-gateway-enterprise" {
+  targets = [
+    "sync-gateway-enterprise-1_3_0-274",
+    "sync-gateway-enterprise-1_3_1-16",
+    "sync-gateway-enterprise-1_4_1-3",
+    "sync-gateway-enterprise-1_5_0-377",
+    "sync-gateway-enterprise-1_5_1",
+    "sync-gateway-enterprise-2_0_0-devbuild",
+    "sync-gateway-enterprise-2_0_0",
+    "sync-gateway-enterprise-2_1_0",
+    "sync-gateway-enterprise-2_1_1",
+    "sync-gateway-enterprise-2_1_2",
+    "sync-gateway-enterprise-2_1_3",
+    "sync-gateway-enterprise-2_5_0",
+    "sync-gateway-enterprise-2_5_1",
+    "sync-gateway-enterprise-2_6_0",
+    "sync-gateway-enterprise-2_6_1",
+    "sync-gateway-enterprise-2_7_0",
+    "sync-gateway-enterprise-2_7_1",
+    "sync-gateway-enterprise-2_7_2",
+    "sync-gateway-enterprise-2_7_3",
+    "sync-gateway-enterprise-2_7_4",
+    "sync-gateway-enterprise-2_8_0",
+    "sync-gateway-enterprise-2_8_2",
+    "sync-gateway-enterprise-2_8_3",
+    "sync-gateway-enterprise-2_8_4",
+    "sync-gateway-enterprise-3_0_3",
+    "sync-gateway-enterprise-3_0_4",
+    "sync-gateway-enterprise-3_0_5",
+    "sync-gateway-enterprise-3_0_7",
+    "sync-gateway-enterprise-3_0_8",
+    "sync-gateway-enterprise-3_0_9",
+    "sync-gateway-enterprise-3_1_0",
+    "sync-gateway-enterprise-3_1_1",
+    "sync-gateway-enterprise-3_1_2",
+    "sync-gateway-enterprise-3_1_3",
+    "sync-gateway-enterprise-3_1_5",
+    "sync-gateway-enterprise-3_1_6",
+    "sync-gateway-enterprise-3_1_7",
+    "sync-gateway-enterprise-3_1_8",
+    "sync-gateway-enterprise-3_1_9",
+    "sync-gateway-enterprise-3_1_10",
+    "sync-gateway-enterprise-3_1_11",
+    "sync-gateway-enterprise-3_1_12",
+    "sync-gateway-enterprise-3_2_0",
+    "sync-gateway-enterprise-3_2_1",
+    "sync-gateway-enterprise-3_2_2",
+    "sync-gateway-enterprise-3_2_3",
+    "sync-gateway-enterprise-3_2_4",
+    "sync-gateway-enterprise-3_2_5",
+    "sync-gateway-enterprise-3_2_6",
+    "sync-gateway-enterprise-3_2_7",
+    "sync-gateway-enterprise-3_3_0",
+    "sync-gateway-enterprise-3_3_1",
+    "sync-gateway-enterprise-3_3_2",
+    "sync-gateway-enterprise-3_3_3",
+    "sync-gateway-enterprise-3_3_4",
+    "sync-gateway-enterprise-4_0_0",
+    "sync-gateway-enterprise-4_0_1",
+    "sync-gateway-enterprise-4_0_2",
+    "sync-gateway-enterprise-4_0_3",
+    "sync-gateway-enterprise-4_0_4",
+  ]
+}
+
+group "server-sandbox" {
+  targets = [
+    "server-sandbox-enterprise",
+  ]
+}
+
+group "server-sandbox-enterprise" {
+  targets = [
+    "server-sandbox-enterprise-6_6_6",
+    "server-sandbox-enterprise-7_0_4",
+    "server-sandbox-enterprise-7_0_5",
+    "server-sandbox-enterprise-7_1_0",
+    "server-sandbox-enterprise-7_1_1",
+    "server-sandbox-enterprise-7_1_3",
+    "server-sandbox-enterprise-7_1_4",
+    "server-sandbox-enterprise-7_1_6",
+    "server-sandbox-enterprise-7_2_2",
+    "server-sandbox-enterprise-7_2_3",
+    "server-sandbox-enterprise-7_2_4",
+    "server-sandbox-enterprise-7_2_5",
+    "server-sandbox-enterprise-7_2_8",
+    "server-sandbox-enterprise-7_2_9",
+    "server-sandbox-enterprise-7_6_0",
+    "server-sandbox-enterprise-7_6_1",
+    "server-sandbox-enterprise-7_6_2",
+    "server-sandbox-enterprise-7_6_3",
+    "server-sandbox-enterprise-7_6_4",
+    "server-sandbox-enterprise-7_6_5",
+    "server-sandbox-enterprise-7_6_6",
+    "server-sandbox-enterprise-7_6_7",
+    "server-sandbox-enterprise-7_6_9",
+    "server-sandbox-enterprise-7_6_10",
+    "server-sandbox-enterprise-8_0_0",
+    "server-sandbox-enterprise-8_0_1",
+  ]
+}
+
+group "couchbase-edge-server" {
+  targets = [
+    "couchbase-edge-server-enterprise",
+  ]
+}
+
+group "couchbase-edge-server-enterprise" {
+  targets = [
+    "couchbase-edge-server-enterprise-1_0_0",
+    "couchbase-edge-server-enterprise-1_0_1",
+  ]
+}
+
+group "enterprise-analytics" {
+  targets = [
+    "enterprise-analytics-enterprise",
+  ]
+}
+
+group "enterprise-analytics-enterprise" {
+  targets = [
+    "enterprise-analytics-enterprise-2_0_0",
+    "enterprise-analytics-enterprise-2_1_0",
+    "enterprise-analytics-enterprise-2_1_1",
+  ]
+}
+
+target "couchbase-server-community-4_0_0" {
+  context = "community/couchbase-server/4.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-4.0.0", "couchbase/server:community-4.0"]
+}
+
+target "couchbase-server-community-4_1_0" {
+  context = "community/couchbase-server/4.1.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-4.1.0"]
+}
+
+target "couchbase-server-community-4_1_1" {
+  context = "community/couchbase-server/4.1.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-4.1.1", "couchbase/server:community-4.1"]
+}
+
+target "couchbase-server-community-4_5_0" {
+  context = "community/couchbase-server/4.5.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-4.5.0"]
+}
+
+target "couchbase-server-community-4_5_1" {
+  context = "community/couchbase-server/4.5.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-4.5.1", "couchbase/server:community-4.5"]
+}
+
+target "couchbase-server-community-5_0_1" {
+  context = "community/couchbase-server/5.0.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-5.0.1", "couchbase/server:community-5.0"]
+}
+
+target "couchbase-server-community-5_1_1" {
+  context = "community/couchbase-server/5.1.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-5.1.1", "couchbase/server:community-5.1"]
+}
+
+target "couchbase-server-community-6_0_0" {
+  context = "community/couchbase-server/6.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-6.0.0", "couchbase/server:community-6.0"]
+}
+
+target "couchbase-server-community-6_5_0" {
+  context = "community/couchbase-server/6.5.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-6.5.0"]
+}
+
+target "couchbase-server-community-6_5_1" {
+  context = "community/couchbase-server/6.5.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-6.5.1", "couchbase/server:community-6.5"]
+}
+
+target "couchbase-server-community-6_6_0" {
+  context = "community/couchbase-server/6.6.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-6.6.0", "couchbase/server:community-6.6"]
+}
+
+target "couchbase-server-community-7_0_0-beta" {
+  context = "community/couchbase-server/7.0.0-beta"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-7.0.0-beta"]
+}
+
+target "couchbase-server-community-7_0_0" {
+  context = "community/couchbase-server/7.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-7.0.0"]
+}
+
+target "couchbase-server-community-7_0_1" {
+  context = "community/couchbase-server/7.0.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-7.0.1"]
+}
+
+target "couchbase-server-community-7_0_2" {
+  context = "community/couchbase-server/7.0.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:community-7.0.2", "couchbase/server:community-7.0"]
+}
+
+target "couchbase-server-community-7_1_0" {
+  context = "community/couchbase-server/7.1.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-7.1.0"]
+}
+
+target "couchbase-server-community-7_1_1" {
+  context = "community/couchbase-server/7.1.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-7.1.1", "couchbase/server:community-7.1"]
+}
+
+target "couchbase-server-community-7_2_0" {
+  context = "community/couchbase-server/7.2.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-7.2.0"]
+}
+
+target "couchbase-server-community-7_2_2" {
+  context = "community/couchbase-server/7.2.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-7.2.2"]
+}
+
+target "couchbase-server-community-7_2_4" {
+  context = "community/couchbase-server/7.2.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-7.2.4", "couchbase/server:community-7.2"]
+}
+
+target "couchbase-server-community-7_6_0" {
+  context = "community/couchbase-server/7.6.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-7.6.0"]
+}
+
+target "couchbase-server-community-7_6_1" {
+  context = "community/couchbase-server/7.6.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-7.6.1"]
+}
+
+target "couchbase-server-community-7_6_2" {
+  context = "community/couchbase-server/7.6.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-7.6.2", "couchbase/server:community-7.6"]
+}
+
+target "couchbase-server-community-8_0_0" {
+  context = "community/couchbase-server/8.0.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-8.0.0"]
+}
+
+target "couchbase-server-community-8_0_1" {
+  context = "community/couchbase-server/8.0.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-8.0.1", "couchbase/server:community-8.0", "couchbase/server:community"]
+}
+
+target "couchbase-server-enterprise-4_0_0" {
+  context = "enterprise/couchbase-server/4.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.0.0", "couchbase/server:4.0.0", "couchbase/server:enterprise-4.0", "couchbase/server:4.0"]
+}
+
+target "couchbase-server-enterprise-4_1_0" {
+  context = "enterprise/couchbase-server/4.1.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.1.0", "couchbase/server:4.1.0"]
+}
+
+target "couchbase-server-enterprise-4_1_1" {
+  context = "enterprise/couchbase-server/4.1.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.1.1", "couchbase/server:4.1.1"]
+}
+
+target "couchbase-server-enterprise-4_1_2" {
+  context = "enterprise/couchbase-server/4.1.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.1.2", "couchbase/server:4.1.2", "couchbase/server:enterprise-4.1", "couchbase/server:4.1"]
+}
+
+target "couchbase-server-enterprise-4_5_0" {
+  context = "enterprise/couchbase-server/4.5.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.5.0", "couchbase/server:4.5.0"]
+}
+
+target "couchbase-server-enterprise-4_5_1" {
+  context = "enterprise/couchbase-server/4.5.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.5.1", "couchbase/server:4.5.1", "couchbase/server:enterprise-4.5", "couchbase/server:4.5"]
+}
+
+target "couchbase-server-enterprise-4_6_0" {
+  context = "enterprise/couchbase-server/4.6.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.6.0", "couchbase/server:4.6.0"]
+}
+
+target "couchbase-server-enterprise-4_6_1" {
+  context = "enterprise/couchbase-server/4.6.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.6.1", "couchbase/server:4.6.1"]
+}
+
+target "couchbase-server-enterprise-4_6_2" {
+  context = "enterprise/couchbase-server/4.6.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.6.2", "couchbase/server:4.6.2"]
+}
+
+target "couchbase-server-enterprise-4_6_3" {
+  context = "enterprise/couchbase-server/4.6.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.6.3", "couchbase/server:4.6.3"]
+}
+
+target "couchbase-server-enterprise-4_6_4" {
+  context = "enterprise/couchbase-server/4.6.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.6.4", "couchbase/server:4.6.4"]
+}
+
+target "couchbase-server-enterprise-4_6_5" {
+  context = "enterprise/couchbase-server/4.6.5"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.6.5", "couchbase/server:4.6.5", "couchbase/server:enterprise-4.6", "couchbase/server:4.6"]
+}
+
+target "couchbase-server-enterprise-5_0_1" {
+  context = "enterprise/couchbase-server/5.0.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.0.1", "couchbase/server:5.0.1", "couchbase/server:enterprise-5.0", "couchbase/server:5.0"]
+}
+
+target "couchbase-server-enterprise-5_1_0" {
+  context = "enterprise/couchbase-server/5.1.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.1.0", "couchbase/server:5.1.0"]
+}
+
+target "couchbase-server-enterprise-5_1_1" {
+  context = "enterprise/couchbase-server/5.1.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.1.1", "couchbase/server:5.1.1"]
+}
+
+target "couchbase-server-enterprise-5_1_2" {
+  context = "enterprise/couchbase-server/5.1.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.1.2", "couchbase/server:5.1.2"]
+}
+
+target "couchbase-server-enterprise-5_1_3" {
+  context = "enterprise/couchbase-server/5.1.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.1.3", "couchbase/server:5.1.3", "couchbase/server:enterprise-5.1", "couchbase/server:5.1"]
+}
+
+target "couchbase-server-enterprise-5_5_0" {
+  context = "enterprise/couchbase-server/5.5.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.5.0", "couchbase/server:5.5.0"]
+}
+
+target "couchbase-server-enterprise-5_5_1" {
+  context = "enterprise/couchbase-server/5.5.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.5.1", "couchbase/server:5.5.1"]
+}
+
+target "couchbase-server-enterprise-5_5_2" {
+  context = "enterprise/couchbase-server/5.5.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.5.2", "couchbase/server:5.5.2"]
+}
+
+target "couchbase-server-enterprise-5_5_3" {
+  context = "enterprise/couchbase-server/5.5.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.5.3", "couchbase/server:5.5.3"]
+}
+
+target "couchbase-server-enterprise-5_5_4" {
+  context = "enterprise/couchbase-server/5.5.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.5.4", "couchbase/server:5.5.4"]
+}
+
+target "couchbase-server-enterprise-5_5_5" {
+  context = "enterprise/couchbase-server/5.5.5"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.5.5", "couchbase/server:5.5.5"]
+}
+
+target "couchbase-server-enterprise-5_5_6" {
+  context = "enterprise/couchbase-server/5.5.6"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.5.6", "couchbase/server:5.5.6", "couchbase/server:enterprise-5.5", "couchbase/server:5.5"]
+}
+
+target "couchbase-server-enterprise-6_0_0" {
+  context = "enterprise/couchbase-server/6.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.0.0", "couchbase/server:6.0.0"]
+}
+
+target "couchbase-server-enterprise-6_0_1" {
+  context = "enterprise/couchbase-server/6.0.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.0.1", "couchbase/server:6.0.1"]
+}
+
+target "couchbase-server-enterprise-6_0_2" {
+  context = "enterprise/couchbase-server/6.0.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.0.2", "couchbase/server:6.0.2"]
+}
+
+target "couchbase-server-enterprise-6_0_3" {
+  context = "enterprise/couchbase-server/6.0.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.0.3", "couchbase/server:6.0.3"]
+}
+
+target "couchbase-server-enterprise-6_0_4" {
+  context = "enterprise/couchbase-server/6.0.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.0.4", "couchbase/server:6.0.4"]
+}
+
+target "couchbase-server-enterprise-6_0_5" {
+  context = "enterprise/couchbase-server/6.0.5"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.0.5", "couchbase/server:6.0.5", "couchbase/server:enterprise-6.0", "couchbase/server:6.0"]
+}
+
+target "couchbase-server-enterprise-6_5_0-beta" {
+  context = "enterprise/couchbase-server/6.5.0-beta"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.5.0-beta", "couchbase/server:6.5.0-beta"]
+}
+
+target "couchbase-server-enterprise-6_5_0-beta2" {
+  context = "enterprise/couchbase-server/6.5.0-beta2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.5.0-beta2", "couchbase/server:6.5.0-beta2"]
+}
+
+target "couchbase-server-enterprise-6_5_0" {
+  context = "enterprise/couchbase-server/6.5.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.5.0", "couchbase/server:6.5.0"]
+}
+
+target "couchbase-server-enterprise-6_5_1" {
+  context = "enterprise/couchbase-server/6.5.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.5.1", "couchbase/server:6.5.1"]
+}
+
+target "couchbase-server-enterprise-6_5_2" {
+  context = "enterprise/couchbase-server/6.5.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.5.2", "couchbase/server:6.5.2", "couchbase/server:enterprise-6.5", "couchbase/server:6.5"]
+}
+
+target "couchbase-server-enterprise-6_6_0" {
+  context = "enterprise/couchbase-server/6.6.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.6.0", "couchbase/server:6.6.0"]
+}
+
+target "couchbase-server-enterprise-6_6_1" {
+  context = "enterprise/couchbase-server/6.6.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.6.1", "couchbase/server:6.6.1"]
+}
+
+target "couchbase-server-enterprise-6_6_2" {
+  context = "enterprise/couchbase-server/6.6.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.6.2", "couchbase/server:6.6.2"]
+}
+
+target "couchbase-server-enterprise-6_6_3" {
+  context = "enterprise/couchbase-server/6.6.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.6.3", "couchbase/server:6.6.3"]
+}
+
+target "couchbase-server-enterprise-6_6_4" {
+  context = "enterprise/couchbase-server/6.6.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.6.4", "couchbase/server:6.6.4"]
+}
+
+target "couchbase-server-enterprise-6_6_5" {
+  context = "enterprise/couchbase-server/6.6.5"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.6.5", "couchbase/server:6.6.5"]
+}
+
+target "couchbase-server-enterprise-6_6_6" {
+  context = "enterprise/couchbase-server/6.6.6"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.6.6", "couchbase/server:6.6.6", "couchbase/server:enterprise-6.6", "couchbase/server:6.6"]
+}
+
+target "couchbase-server-enterprise-7_0_0-beta" {
+  context = "enterprise/couchbase-server/7.0.0-beta"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-7.0.0-beta", "couchbase/server:7.0.0-beta"]
+}
+
+target "couchbase-server-enterprise-7_0_0" {
+  context = "enterprise/couchbase-server/7.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-7.0.0", "couchbase/server:7.0.0"]
+}
+
+target "couchbase-server-enterprise-7_0_0-5017" {
+  context = "enterprise/couchbase-server/7.0.0-5017"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-7.0.0-5017", "couchbase/server:7.0.0-5017"]
+}
+
+target "couchbase-server-enterprise-7_0_1" {
+  context = "enterprise/couchbase-server/7.0.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-7.0.1", "couchbase/server:7.0.1"]
+}
+
+target "couchbase-server-enterprise-7_0_2" {
+  context = "enterprise/couchbase-server/7.0.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-7.0.2", "couchbase/server:7.0.2"]
+}
+
+target "couchbase-server-enterprise-7_0_3" {
+  context = "enterprise/couchbase-server/7.0.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-7.0.3", "couchbase/server:7.0.3"]
+}
+
+target "couchbase-server-enterprise-7_0_4" {
+  context = "enterprise/couchbase-server/7.0.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-7.0.4", "couchbase/server:7.0.4"]
+}
+
+target "couchbase-server-enterprise-7_0_5" {
+  context = "enterprise/couchbase-server/7.0.5"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-7.0.5", "couchbase/server:7.0.5", "couchbase/server:enterprise-7.0", "couchbase/server:7.0"]
+}
+
+target "couchbase-server-enterprise-7_1_0" {
+  context = "enterprise/couchbase-server/7.1.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.1.0", "couchbase/server:7.1.0"]
+}
+
+target "couchbase-server-enterprise-7_1_1" {
+  context = "enterprise/couchbase-server/7.1.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.1.1", "couchbase/server:7.1.1"]
+}
+
+target "couchbase-server-enterprise-7_1_2" {
+  context = "enterprise/couchbase-server/7.1.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.1.2", "couchbase/server:7.1.2"]
+}
+
+target "couchbase-server-enterprise-7_1_3" {
+  context = "enterprise/couchbase-server/7.1.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.1.3", "couchbase/server:7.1.3"]
+}
+
+target "couchbase-server-enterprise-7_1_4" {
+  context = "enterprise/couchbase-server/7.1.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.1.4", "couchbase/server:7.1.4"]
+}
+
+target "couchbase-server-enterprise-7_1_5" {
+  context = "enterprise/couchbase-server/7.1.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.1.5", "couchbase/server:7.1.5"]
+}
+
+target "couchbase-server-enterprise-7_1_6" {
+  context = "enterprise/couchbase-server/7.1.6"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.1.6", "couchbase/server:7.1.6", "couchbase/server:enterprise-7.1", "couchbase/server:7.1"]
+}
+
+target "couchbase-server-enterprise-7_2_0" {
+  context = "enterprise/couchbase-server/7.2.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.0", "couchbase/server:7.2.0"]
+}
+
+target "couchbase-server-enterprise-7_2_2" {
+  context = "enterprise/couchbase-server/7.2.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.2", "couchbase/server:7.2.2"]
+}
+
+target "couchbase-server-enterprise-7_2_3" {
+  context = "enterprise/couchbase-server/7.2.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.3", "couchbase/server:7.2.3"]
+}
+
+target "couchbase-server-enterprise-7_2_4" {
+  context = "enterprise/couchbase-server/7.2.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.4", "couchbase/server:7.2.4"]
+}
+
+target "couchbase-server-enterprise-7_2_5" {
+  context = "enterprise/couchbase-server/7.2.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.5", "couchbase/server:7.2.5"]
+}
+
+target "couchbase-server-enterprise-7_2_6" {
+  context = "enterprise/couchbase-server/7.2.6"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.6", "couchbase/server:7.2.6"]
+}
+
+target "couchbase-server-enterprise-7_2_7" {
+  context = "enterprise/couchbase-server/7.2.7"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.7", "couchbase/server:7.2.7"]
+}
+
+target "couchbase-server-enterprise-7_2_8" {
+  context = "enterprise/couchbase-server/7.2.8"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.8", "couchbase/server:7.2.8"]
+}
+
+target "couchbase-server-enterprise-7_2_9" {
+  context = "enterprise/couchbase-server/7.2.9"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.9", "couchbase/server:7.2.9", "couchbase/server:enterprise-7.2", "couchbase/server:7.2"]
+}
+
+target "couchbase-server-enterprise-7_6_0" {
+  context = "enterprise/couchbase-server/7.6.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.0", "couchbase/server:7.6.0"]
+}
+
+target "couchbase-server-enterprise-7_6_1" {
+  context = "enterprise/couchbase-server/7.6.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.1", "couchbase/server:7.6.1"]
+}
+
+target "couchbase-server-enterprise-7_6_2" {
+  context = "enterprise/couchbase-server/7.6.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.2", "couchbase/server:7.6.2"]
+}
+
+target "couchbase-server-enterprise-7_6_3" {
+  context = "enterprise/couchbase-server/7.6.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.3", "couchbase/server:7.6.3"]
+}
+
+target "couchbase-server-enterprise-7_6_4" {
+  context = "enterprise/couchbase-server/7.6.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.4", "couchbase/server:7.6.4"]
+}
+
+target "couchbase-server-enterprise-7_6_5" {
+  context = "enterprise/couchbase-server/7.6.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.5", "couchbase/server:7.6.5"]
+}
+
+target "couchbase-server-enterprise-7_6_6" {
+  context = "enterprise/couchbase-server/7.6.6"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.6", "couchbase/server:7.6.6"]
+}
+
+target "couchbase-server-enterprise-7_6_7" {
+  context = "enterprise/couchbase-server/7.6.7"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.7", "couchbase/server:7.6.7"]
+}
+
+target "couchbase-server-enterprise-7_6_8" {
+  context = "enterprise/couchbase-server/7.6.8"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.8", "couchbase/server:7.6.8"]
+}
+
+target "couchbase-server-enterprise-7_6_9" {
+  context = "enterprise/couchbase-server/7.6.9"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.9", "couchbase/server:7.6.9"]
+}
+
+target "couchbase-server-enterprise-7_6_10" {
+  context = "enterprise/couchbase-server/7.6.10"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.10", "couchbase/server:7.6.10"]
+}
+
+target "couchbase-server-enterprise-7_6_11" {
+  context = "enterprise/couchbase-server/7.6.11"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.11", "couchbase/server:7.6.11", "couchbase/server:enterprise-7.6", "couchbase/server:7.6"]
+}
+
+target "couchbase-server-enterprise-8_0_0" {
+  context = "enterprise/couchbase-server/8.0.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-8.0.0", "couchbase/server:8.0.0"]
+}
+
+target "couchbase-server-enterprise-8_0_1" {
+  context = "enterprise/couchbase-server/8.0.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-8.0.1", "couchbase/server:8.0.1", "couchbase/server:enterprise-8.0", "couchbase/server:8.0", "couchbase/server:enterprise", "couchbase/server:latest"]
+}
+
+target "sync-gateway-community-1_0_4" {
+  context = "community/sync-gateway/1.0.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.0.4", "couchbase/sync-gateway:community-1.0"]
+}
+
+target "sync-gateway-community-1_1_0-forestdb_bucket" {
+  context = "community/sync-gateway/1.1.0-forestdb_bucket"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.1.0-forestdb_bucket"]
+}
+
+target "sync-gateway-community-1_1_0" {
+  context = "community/sync-gateway/1.1.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.1.0"]
+}
+
+target "sync-gateway-community-1_1_1" {
+  context = "community/sync-gateway/1.1.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.1.1", "couchbase/sync-gateway:community-1.1"]
+}
+
+target "sync-gateway-community-1_2_0-rc0" {
+  context = "community/sync-gateway/1.2.0-rc0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.2.0-rc0"]
+}
+
+target "sync-gateway-community-1_2_0-rc1" {
+  context = "community/sync-gateway/1.2.0-rc1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.2.0-rc1"]
+}
+
+target "sync-gateway-community-1_2_0" {
+  context = "community/sync-gateway/1.2.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.2.0"]
+}
+
+target "sync-gateway-community-1_2_1" {
+  context = "community/sync-gateway/1.2.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.2.1", "couchbase/sync-gateway:community-1.2"]
+}
+
+target "sync-gateway-community-1_3_0-274" {
+  context = "community/sync-gateway/1.3.0-274"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.3.0-274"]
+}
+
+target "sync-gateway-community-1_3_1-16" {
+  context = "community/sync-gateway/1.3.1-16"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.3.1-16"]
+}
+
+target "sync-gateway-community-1_4_0-2" {
+  context = "community/sync-gateway/1.4.0-2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.4.0-2"]
+}
+
+target "sync-gateway-community-1_4_1-3" {
+  context = "community/sync-gateway/1.4.1-3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.4.1-3"]
+}
+
+target "sync-gateway-community-1_5_0-377" {
+  context = "community/sync-gateway/1.5.0-377"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.5.0-377"]
+}
+
+target "sync-gateway-community-1_5_1" {
+  context = "community/sync-gateway/1.5.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.5.1", "couchbase/sync-gateway:community-1.5"]
+}
+
+target "sync-gateway-community-2_0_0-devbuild" {
+  context = "community/sync-gateway/2.0.0-devbuild"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.0.0-devbuild"]
+}
+
+target "sync-gateway-community-2_0_0" {
+  context = "community/sync-gateway/2.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.0.0", "couchbase/sync-gateway:community-2.0"]
+}
+
+target "sync-gateway-community-2_1_0" {
+  context = "community/sync-gateway/2.1.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.1.0"]
+}
+
+target "sync-gateway-community-2_1_1" {
+  context = "community/sync-gateway/2.1.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.1.1"]
+}
+
+target "sync-gateway-community-2_1_2" {
+  context = "community/sync-gateway/2.1.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.1.2"]
+}
+
+target "sync-gateway-community-2_1_3" {
+  context = "community/sync-gateway/2.1.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.1.3", "couchbase/sync-gateway:community-2.1"]
+}
+
+target "sync-gateway-community-2_5_0" {
+  context = "community/sync-gateway/2.5.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.5.0"]
+}
+
+target "sync-gateway-community-2_5_1" {
+  context = "community/sync-gateway/2.5.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.5.1", "couchbase/sync-gateway:community-2.5"]
+}
+
+target "sync-gateway-community-2_6_0" {
+  context = "community/sync-gateway/2.6.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.6.0"]
+}
+
+target "sync-gateway-community-2_6_1" {
+  context = "community/sync-gateway/2.6.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.6.1", "couchbase/sync-gateway:community-2.6"]
+}
+
+target "sync-gateway-community-2_7_0" {
+  context = "community/sync-gateway/2.7.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.7.0"]
+}
+
+target "sync-gateway-community-2_7_1" {
+  context = "community/sync-gateway/2.7.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.7.1"]
+}
+
+target "sync-gateway-community-2_7_2" {
+  context = "community/sync-gateway/2.7.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.7.2"]
+}
+
+target "sync-gateway-community-2_7_3" {
+  context = "community/sync-gateway/2.7.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.7.3"]
+}
+
+target "sync-gateway-community-2_7_4" {
+  context = "community/sync-gateway/2.7.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.7.4", "couchbase/sync-gateway:community-2.7"]
+}
+
+target "sync-gateway-community-2_8_0" {
+  context = "community/sync-gateway/2.8.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.8.0"]
+}
+
+target "sync-gateway-community-2_8_2" {
+  context = "community/sync-gateway/2.8.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.8.2"]
+}
+
+target "sync-gateway-community-2_8_3" {
+  context = "community/sync-gateway/2.8.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.8.3"]
+}
+
+target "sync-gateway-community-2_8_4" {
+  context = "community/sync-gateway/2.8.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.8.4", "couchbase/sync-gateway:community-2.8"]
+}
+
+target "sync-gateway-community-3_0_3" {
+  context = "community/sync-gateway/3.0.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-3.0.3"]
+}
+
+target "sync-gateway-community-3_0_4" {
+  context = "community/sync-gateway/3.0.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.0.4"]
+}
+
+target "sync-gateway-community-3_0_5" {
+  context = "community/sync-gateway/3.0.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.0.5"]
+}
+
+target "sync-gateway-community-3_0_7" {
+  context = "community/sync-gateway/3.0.7"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.0.7"]
+}
+
+target "sync-gateway-community-3_0_8" {
+  context = "community/sync-gateway/3.0.8"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.0.8"]
+}
+
+target "sync-gateway-community-3_0_9" {
+  context = "community/sync-gateway/3.0.9"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.0.9", "couchbase/sync-gateway:community-3.0"]
+}
+
+target "sync-gateway-community-3_1_0" {
+  context = "community/sync-gateway/3.1.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.0"]
+}
+
+target "sync-gateway-community-3_1_1" {
+  context = "community/sync-gateway/3.1.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.1"]
+}
+
+target "sync-gateway-community-3_1_2" {
+  context = "community/sync-gateway/3.1.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.2"]
+}
+
+target "sync-gateway-community-3_1_3" {
+  context = "community/sync-gateway/3.1.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.3"]
+}
+
+target "sync-gateway-community-3_1_5" {
+  context = "community/sync-gateway/3.1.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.5"]
+}
+
+target "sync-gateway-community-3_1_6" {
+  context = "community/sync-gateway/3.1.6"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.6"]
+}
+
+target "sync-gateway-community-3_1_7" {
+  context = "community/sync-gateway/3.1.7"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.7"]
+}
+
+target "sync-gateway-community-3_1_8" {
+  context = "community/sync-gateway/3.1.8"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.8"]
+}
+
+target "sync-gateway-community-3_1_9" {
+  context = "community/sync-gateway/3.1.9"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.9"]
+}
+
+target "sync-gateway-community-3_1_10" {
+  context = "community/sync-gateway/3.1.10"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.10"]
+}
+
+target "sync-gateway-community-3_1_11" {
+  context = "community/sync-gateway/3.1.11"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.11"]
+}
+
+target "sync-gateway-community-3_1_12" {
+  context = "community/sync-gateway/3.1.12"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.1.12", "couchbase/sync-gateway:community-3.1"]
+}
+
+target "sync-gateway-community-3_2_0" {
+  context = "community/sync-gateway/3.2.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.2.0"]
+}
+
+target "sync-gateway-community-3_2_1" {
+  context = "community/sync-gateway/3.2.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.2.1"]
+}
+
+target "sync-gateway-community-3_2_2" {
+  context = "community/sync-gateway/3.2.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.2.2"]
+}
+
+target "sync-gateway-community-3_2_3" {
+  context = "community/sync-gateway/3.2.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.2.3"]
+}
+
+target "sync-gateway-community-3_2_4" {
+  context = "community/sync-gateway/3.2.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.2.4"]
+}
+
+target "sync-gateway-community-3_2_5" {
+  context = "community/sync-gateway/3.2.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.2.5"]
+}
+
+target "sync-gateway-community-3_2_6" {
+  context = "community/sync-gateway/3.2.6"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.2.6"]
+}
+
+target "sync-gateway-community-3_2_7" {
+  context = "community/sync-gateway/3.2.7"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.2.7", "couchbase/sync-gateway:community-3.2"]
+}
+
+target "sync-gateway-community-3_3_0" {
+  context = "community/sync-gateway/3.3.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.3.0"]
+}
+
+target "sync-gateway-community-3_3_1" {
+  context = "community/sync-gateway/3.3.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.3.1"]
+}
+
+target "sync-gateway-community-3_3_2" {
+  context = "community/sync-gateway/3.3.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.3.2"]
+}
+
+target "sync-gateway-community-3_3_3" {
+  context = "community/sync-gateway/3.3.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.3.3"]
+}
+
+target "sync-gateway-community-3_3_4" {
+  context = "community/sync-gateway/3.3.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-3.3.4", "couchbase/sync-gateway:community-3.3"]
+}
+
+target "sync-gateway-community-4_0_0" {
+  context = "community/sync-gateway/4.0.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-4.0.0"]
+}
+
+target "sync-gateway-community-4_0_1" {
+  context = "community/sync-gateway/4.0.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-4.0.1"]
+}
+
+target "sync-gateway-community-4_0_2" {
+  context = "community/sync-gateway/4.0.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-4.0.2"]
+}
+
+target "sync-gateway-community-4_0_3" {
+  context = "community/sync-gateway/4.0.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-4.0.3"]
+}
+
+target "sync-gateway-community-4_0_4" {
+  context = "community/sync-gateway/4.0.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:community-4.0.4", "couchbase/sync-gateway:community-4.0", "couchbase/sync-gateway:community"]
+}
+
+target "sync-gateway-enterprise-1_3_0-274" {
+  context = "enterprise/sync-gateway/1.3.0-274"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-1.3.0-274", "couchbase/sync-gateway:1.3.0-274"]
+}
+
+target "sync-gateway-enterprise-1_3_1-16" {
+  context = "enterprise/sync-gateway/1.3.1-16"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-1.3.1-16", "couchbase/sync-gateway:1.3.1-16"]
+}
+
+target "sync-gateway-enterprise-1_4_1-3" {
+  context = "enterprise/sync-gateway/1.4.1-3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-1.4.1-3", "couchbase/sync-gateway:1.4.1-3"]
+}
+
+target "sync-gateway-enterprise-1_5_0-377" {
+  context = "enterprise/sync-gateway/1.5.0-377"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-1.5.0-377", "couchbase/sync-gateway:1.5.0-377"]
+}
+
+target "sync-gateway-enterprise-1_5_1" {
+  context = "enterprise/sync-gateway/1.5.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-1.5.1", "couchbase/sync-gateway:1.5.1", "couchbase/sync-gateway:enterprise-1.5", "couchbase/sync-gateway:1.5"]
+}
+
+target "sync-gateway-enterprise-2_0_0-devbuild" {
+  context = "enterprise/sync-gateway/2.0.0-devbuild"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.0.0-devbuild", "couchbase/sync-gateway:2.0.0-devbuild"]
+}
+
+target "sync-gateway-enterprise-2_0_0" {
+  context = "enterprise/sync-gateway/2.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.0.0", "couchbase/sync-gateway:2.0.0", "couchbase/sync-gateway:enterprise-2.0", "couchbase/sync-gateway:2.0"]
+}
+
+target "sync-gateway-enterprise-2_1_0" {
+  context = "enterprise/sync-gateway/2.1.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.1.0", "couchbase/sync-gateway:2.1.0"]
+}
+
+target "sync-gateway-enterprise-2_1_1" {
+  context = "enterprise/sync-gateway/2.1.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.1.1", "couchbase/sync-gateway:2.1.1"]
+}
+
+target "sync-gateway-enterprise-2_1_2" {
+  context = "enterprise/sync-gateway/2.1.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.1.2", "couchbase/sync-gateway:2.1.2"]
+}
+
+target "sync-gateway-enterprise-2_1_3" {
+  context = "enterprise/sync-gateway/2.1.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.1.3", "couchbase/sync-gateway:2.1.3", "couchbase/sync-gateway:enterprise-2.1", "couchbase/sync-gateway:2.1"]
+}
+
+target "sync-gateway-enterprise-2_5_0" {
+  context = "enterprise/sync-gateway/2.5.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.5.0", "couchbase/sync-gateway:2.5.0"]
+}
+
+target "sync-gateway-enterprise-2_5_1" {
+  context = "enterprise/sync-gateway/2.5.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.5.1", "couchbase/sync-gateway:2.5.1", "couchbase/sync-gateway:enterprise-2.5", "couchbase/sync-gateway:2.5"]
+}
+
+target "sync-gateway-enterprise-2_6_0" {
+  context = "enterprise/sync-gateway/2.6.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.6.0", "couchbase/sync-gateway:2.6.0"]
+}
+
+target "sync-gateway-enterprise-2_6_1" {
+  context = "enterprise/sync-gateway/2.6.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.6.1", "couchbase/sync-gateway:2.6.1", "couchbase/sync-gateway:enterprise-2.6", "couchbase/sync-gateway:2.6"]
+}
+
+target "sync-gateway-enterprise-2_7_0" {
+  context = "enterprise/sync-gateway/2.7.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.7.0", "couchbase/sync-gateway:2.7.0"]
+}
+
+target "sync-gateway-enterprise-2_7_1" {
+  context = "enterprise/sync-gateway/2.7.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.7.1", "couchbase/sync-gateway:2.7.1"]
+}
+
+target "sync-gateway-enterprise-2_7_2" {
+  context = "enterprise/sync-gateway/2.7.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.7.2", "couchbase/sync-gateway:2.7.2"]
+}
+
+target "sync-gateway-enterprise-2_7_3" {
+  context = "enterprise/sync-gateway/2.7.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.7.3", "couchbase/sync-gateway:2.7.3"]
+}
+
+target "sync-gateway-enterprise-2_7_4" {
+  context = "enterprise/sync-gateway/2.7.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.7.4", "couchbase/sync-gateway:2.7.4", "couchbase/sync-gateway:enterprise-2.7", "couchbase/sync-gateway:2.7"]
+}
+
+target "sync-gateway-enterprise-2_8_0" {
+  context = "enterprise/sync-gateway/2.8.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.8.0", "couchbase/sync-gateway:2.8.0"]
+}
+
+target "sync-gateway-enterprise-2_8_2" {
+  context = "enterprise/sync-gateway/2.8.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.8.2", "couchbase/sync-gateway:2.8.2"]
+}
+
+target "sync-gateway-enterprise-2_8_3" {
+  context = "enterprise/sync-gateway/2.8.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.8.3", "couchbase/sync-gateway:2.8.3"]
+}
+
+target "sync-gateway-enterprise-2_8_4" {
+  context = "enterprise/sync-gateway/2.8.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-2.8.4", "couchbase/sync-gateway:2.8.4", "couchbase/sync-gateway:enterprise-2.8", "couchbase/sync-gateway:2.8"]
+}
+
+target "sync-gateway-enterprise-3_0_3" {
+  context = "enterprise/sync-gateway/3.0.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.0.3", "couchbase/sync-gateway:3.0.3"]
+}
+
+target "sync-gateway-enterprise-3_0_4" {
+  context = "enterprise/sync-gateway/3.0.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.0.4", "couchbase/sync-gateway:3.0.4"]
+}
+
+target "sync-gateway-enterprise-3_0_5" {
+  context = "enterprise/sync-gateway/3.0.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.0.5", "couchbase/sync-gateway:3.0.5"]
+}
+
+target "sync-gateway-enterprise-3_0_7" {
+  context = "enterprise/sync-gateway/3.0.7"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.0.7", "couchbase/sync-gateway:3.0.7"]
+}
+
+target "sync-gateway-enterprise-3_0_8" {
+  context = "enterprise/sync-gateway/3.0.8"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.0.8", "couchbase/sync-gateway:3.0.8"]
+}
+
+target "sync-gateway-enterprise-3_0_9" {
+  context = "enterprise/sync-gateway/3.0.9"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.0.9", "couchbase/sync-gateway:3.0.9", "couchbase/sync-gateway:enterprise-3.0", "couchbase/sync-gateway:3.0"]
+}
+
+target "sync-gateway-enterprise-3_1_0" {
+  context = "enterprise/sync-gateway/3.1.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.0", "couchbase/sync-gateway:3.1.0"]
+}
+
+target "sync-gateway-enterprise-3_1_1" {
+  context = "enterprise/sync-gateway/3.1.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.1", "couchbase/sync-gateway:3.1.1"]
+}
+
+target "sync-gateway-enterprise-3_1_2" {
+  context = "enterprise/sync-gateway/3.1.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.2", "couchbase/sync-gateway:3.1.2"]
+}
+
+target "sync-gateway-enterprise-3_1_3" {
+  context = "enterprise/sync-gateway/3.1.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.3", "couchbase/sync-gateway:3.1.3"]
+}
+
+target "sync-gateway-enterprise-3_1_5" {
+  context = "enterprise/sync-gateway/3.1.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.5", "couchbase/sync-gateway:3.1.5"]
+}
+
+target "sync-gateway-enterprise-3_1_6" {
+  context = "enterprise/sync-gateway/3.1.6"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.6", "couchbase/sync-gateway:3.1.6"]
+}
+
+target "sync-gateway-enterprise-3_1_7" {
+  context = "enterprise/sync-gateway/3.1.7"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.7", "couchbase/sync-gateway:3.1.7"]
+}
+
+target "sync-gateway-enterprise-3_1_8" {
+  context = "enterprise/sync-gateway/3.1.8"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.8", "couchbase/sync-gateway:3.1.8"]
+}
+
+target "sync-gateway-enterprise-3_1_9" {
+  context = "enterprise/sync-gateway/3.1.9"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.9", "couchbase/sync-gateway:3.1.9"]
+}
+
+target "sync-gateway-enterprise-3_1_10" {
+  context = "enterprise/sync-gateway/3.1.10"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.10", "couchbase/sync-gateway:3.1.10"]
+}
+
+target "sync-gateway-enterprise-3_1_11" {
+  context = "enterprise/sync-gateway/3.1.11"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.11", "couchbase/sync-gateway:3.1.11"]
+}
+
+target "sync-gateway-enterprise-3_1_12" {
+  context = "enterprise/sync-gateway/3.1.12"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.1.12", "couchbase/sync-gateway:3.1.12", "couchbase/sync-gateway:enterprise-3.1", "couchbase/sync-gateway:3.1"]
+}
+
+target "sync-gateway-enterprise-3_2_0" {
+  context = "enterprise/sync-gateway/3.2.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.2.0", "couchbase/sync-gateway:3.2.0"]
+}
+
+target "sync-gateway-enterprise-3_2_1" {
+  context = "enterprise/sync-gateway/3.2.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.2.1", "couchbase/sync-gateway:3.2.1"]
+}
+
+target "sync-gateway-enterprise-3_2_2" {
+  context = "enterprise/sync-gateway/3.2.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.2.2", "couchbase/sync-gateway:3.2.2"]
+}
+
+target "sync-gateway-enterprise-3_2_3" {
+  context = "enterprise/sync-gateway/3.2.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.2.3", "couchbase/sync-gateway:3.2.3"]
+}
+
+target "sync-gateway-enterprise-3_2_4" {
+  context = "enterprise/sync-gateway/3.2.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.2.4", "couchbase/sync-gateway:3.2.4"]
+}
+
+target "sync-gateway-enterprise-3_2_5" {
+  context = "enterprise/sync-gateway/3.2.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.2.5", "couchbase/sync-gateway:3.2.5"]
+}
+
+target "sync-gateway-enterprise-3_2_6" {
+  context = "enterprise/sync-gateway/3.2.6"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.2.6", "couchbase/sync-gateway:3.2.6"]
+}
+
+target "sync-gateway-enterprise-3_2_7" {
+  context = "enterprise/sync-gateway/3.2.7"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.2.7", "couchbase/sync-gateway:3.2.7", "couchbase/sync-gateway:enterprise-3.2", "couchbase/sync-gateway:3.2"]
+}
+
+target "sync-gateway-enterprise-3_3_0" {
+  context = "enterprise/sync-gateway/3.3.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.3.0", "couchbase/sync-gateway:3.3.0"]
+}
+
+target "sync-gateway-enterprise-3_3_1" {
+  context = "enterprise/sync-gateway/3.3.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.3.1", "couchbase/sync-gateway:3.3.1"]
+}
+
+target "sync-gateway-enterprise-3_3_2" {
+  context = "enterprise/sync-gateway/3.3.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.3.2", "couchbase/sync-gateway:3.3.2"]
+}
+
+target "sync-gateway-enterprise-3_3_3" {
+  context = "enterprise/sync-gateway/3.3.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.3.3", "couchbase/sync-gateway:3.3.3"]
+}
+
+target "sync-gateway-enterprise-3_3_4" {
+  context = "enterprise/sync-gateway/3.3.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.3.4", "couchbase/sync-gateway:3.3.4", "couchbase/sync-gateway:enterprise-3.3", "couchbase/sync-gateway:3.3"]
+}
+
+target "sync-gateway-enterprise-4_0_0" {
+  context = "enterprise/sync-gateway/4.0.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-4.0.0", "couchbase/sync-gateway:4.0.0"]
+}
+
+target "sync-gateway-enterprise-4_0_1" {
+  context = "enterprise/sync-gateway/4.0.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-4.0.1", "couchbase/sync-gateway:4.0.1"]
+}
+
+target "sync-gateway-enterprise-4_0_2" {
+  context = "enterprise/sync-gateway/4.0.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-4.0.2", "couchbase/sync-gateway:4.0.2"]
+}
+
+target "sync-gateway-enterprise-4_0_3" {
+  context = "enterprise/sync-gateway/4.0.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-4.0.3", "couchbase/sync-gateway:4.0.3"]
+}
+
+target "sync-gateway-enterprise-4_0_4" {
+  context = "enterprise/sync-gateway/4.0.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-4.0.4", "couchbase/sync-gateway:4.0.4", "couchbase/sync-gateway:enterprise-4.0", "couchbase/sync-gateway:4.0", "couchbase/sync-gateway:enterprise", "couchbase/sync-gateway:latest"]
+}
+
+target "server-sandbox-enterprise-6_6_6" {
+  context = "enterprise/server-sandbox/6.6.6"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server-sandbox:6.6.6", "couchbase/server-sandbox:6.6"]
+  contexts = {
+    "couchbase/server:6.6.6" = "target:couchbase-server-enterprise-6_6_6"
+  }
+}
+
+target "server-sandbox-enterprise-7_0_4" {
+  context = "enterprise/server-sandbox/7.0.4"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server-sandbox:7.0.4"]
+  contexts = {
+    "couchbase/server:7.0.4" = "target:couchbase-server-enterprise-7_0_4"
+  }
+}
+
+target "server-sandbox-enterprise-7_0_5" {
+  context = "enterprise/server-sandbox/7.0.5"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server-sandbox:7.0.5", "couchbase/server-sandbox:7.0"]
+  contexts = {
+    "couchbase/server:7.0.5" = "target:couchbase-server-enterprise-7_0_5"
+  }
+}
+
+target "server-sandbox-enterprise-7_1_0" {
+  context = "enterprise/server-sandbox/7.1.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.1.0"]
+  contexts = {
+    "couchbase/server:7.1.0" = "target:couchbase-server-enterprise-7_1_0"
+  }
+}
+
+target "server-sandbox-enterprise-7_1_1" {
+  context = "enterprise/server-sandbox/7.1.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.1.1"]
+  contexts = {
+    "couchbase/server:7.1.1" = "target:couchbase-server-enterprise-7_1_1"
+  }
+}
+
+target "server-sandbox-enterprise-7_1_3" {
+  context = "enterprise/server-sandbox/7.1.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.1.3"]
+  contexts = {
+    "couchbase/server:7.1.3" = "target:couchbase-server-enterprise-7_1_3"
+  }
+}
+
+target "server-sandbox-enterprise-7_1_4" {
+  context = "enterprise/server-sandbox/7.1.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.1.4"]
+  contexts = {
+    "couchbase/server:7.1.4" = "target:couchbase-server-enterprise-7_1_4"
+  }
+}
+
+target "server-sandbox-enterprise-7_1_6" {
+  context = "enterprise/server-sandbox/7.1.6"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.1.6", "couchbase/server-sandbox:7.1"]
+  contexts = {
+    "couchbase/server:7.1.6" = "target:couchbase-server-enterprise-7_1_6"
+  }
+}
+
+target "server-sandbox-enterprise-7_2_2" {
+  context = "enterprise/server-sandbox/7.2.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.2.2"]
+  contexts = {
+    "couchbase/server:7.2.2" = "target:couchbase-server-enterprise-7_2_2"
+  }
+}
+
+target "server-sandbox-enterprise-7_2_3" {
+  context = "enterprise/server-sandbox/7.2.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.2.3"]
+  contexts = {
+    "couchbase/server:7.2.3" = "target:couchbase-server-enterprise-7_2_3"
+  }
+}
+
+target "server-sandbox-enterprise-7_2_4" {
+  context = "enterprise/server-sandbox/7.2.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.2.4"]
+  contexts = {
+    "couchbase/server:7.2.4" = "target:couchbase-server-enterprise-7_2_4"
+  }
+}
+
+target "server-sandbox-enterprise-7_2_5" {
+  context = "enterprise/server-sandbox/7.2.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.2.5"]
+  contexts = {
+    "couchbase/server:7.2.5" = "target:couchbase-server-enterprise-7_2_5"
+  }
+}
+
+target "server-sandbox-enterprise-7_2_8" {
+  context = "enterprise/server-sandbox/7.2.8"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.2.8"]
+  contexts = {
+    "couchbase/server:7.2.8" = "target:couchbase-server-enterprise-7_2_8"
+  }
+}
+
+target "server-sandbox-enterprise-7_2_9" {
+  context = "enterprise/server-sandbox/7.2.9"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.2.9", "couchbase/server-sandbox:7.2"]
+  contexts = {
+    "couchbase/server:7.2.9" = "target:couchbase-server-enterprise-7_2_9"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_0" {
+  context = "enterprise/server-sandbox/7.6.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.0"]
+  contexts = {
+    "couchbase/server:7.6.0" = "target:couchbase-server-enterprise-7_6_0"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_1" {
+  context = "enterprise/server-sandbox/7.6.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.1"]
+  contexts = {
+    "couchbase/server:7.6.1" = "target:couchbase-server-enterprise-7_6_1"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_2" {
+  context = "enterprise/server-sandbox/7.6.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.2"]
+  contexts = {
+    "couchbase/server:7.6.2" = "target:couchbase-server-enterprise-7_6_2"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_3" {
+  context = "enterprise/server-sandbox/7.6.3"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.3"]
+  contexts = {
+    "couchbase/server:7.6.3" = "target:couchbase-server-enterprise-7_6_3"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_4" {
+  context = "enterprise/server-sandbox/7.6.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.4"]
+  contexts = {
+    "couchbase/server:7.6.4" = "target:couchbase-server-enterprise-7_6_4"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_5" {
+  context = "enterprise/server-sandbox/7.6.5"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.5"]
+  contexts = {
+    "couchbase/server:7.6.5" = "target:couchbase-server-enterprise-7_6_5"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_6" {
+  context = "enterprise/server-sandbox/7.6.6"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.6"]
+  contexts = {
+    "couchbase/server:7.6.6" = "target:couchbase-server-enterprise-7_6_6"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_7" {
+  context = "enterprise/server-sandbox/7.6.7"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.7"]
+  contexts = {
+    "couchbase/server:7.6.7" = "target:couchbase-server-enterprise-7_6_7"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_9" {
+  context = "enterprise/server-sandbox/7.6.9"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.9"]
+  contexts = {
+    "couchbase/server:7.6.9" = "target:couchbase-server-enterprise-7_6_9"
+  }
+}
+
+target "server-sandbox-enterprise-7_6_10" {
+  context = "enterprise/server-sandbox/7.6.10"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.6.10", "couchbase/server-sandbox:7.6"]
+  contexts = {
+    "couchbase/server:7.6.10" = "target:couchbase-server-enterprise-7_6_10"
+  }
+}
+
+target "server-sandbox-enterprise-8_0_0" {
+  context = "enterprise/server-sandbox/8.0.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:8.0.0"]
+  contexts = {
+    "couchbase/server:8.0.0" = "target:couchbase-server-enterprise-8_0_0"
+  }
+}
+
+target "server-sandbox-enterprise-8_0_1" {
+  context = "enterprise/server-sandbox/8.0.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:8.0.1", "couchbase/server-sandbox:8.0", "couchbase/server-sandbox:latest"]
+  contexts = {
+    "couchbase/server:8.0.1" = "target:couchbase-server-enterprise-8_0_1"
+  }
+}
+
+target "couchbase-edge-server-enterprise-1_0_0" {
+  context = "enterprise/couchbase-edge-server/1.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/edge-server:1.0.0"]
+}
+
+target "couchbase-edge-server-enterprise-1_0_1" {
+  context = "enterprise/couchbase-edge-server/1.0.1"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/edge-server:1.0.1", "couchbase/edge-server:1.0", "couchbase/edge-server:latest"]
+}
+
+target "enterprise-analytics-enterprise-2_0_0" {
+  context = "enterprise/enterprise-analytics/2.0.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/enterprise-analytics:2.0.0", "couchbase/enterprise-analytics:2.0"]
+}
+
+target "enterprise-analytics-enterprise-2_1_0" {
+  context = "enterprise/enterprise-analytics/2.1.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/enterprise-analytics:2.1.0"]
+}
+
+target "enterprise-analytics-enterprise-2_1_1" {
+  context = "enterprise/enterprise-analytics/2.1.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/enterprise-analytics:2.1.1", "couchbase/enterprise-analytics:2.1", "couchbase/enterprise-analytics:latest"]
+}
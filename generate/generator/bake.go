@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// bakeFilename is the build definition written to the root of the repository
+const bakeFilename = "docker-bake.hcl"
+
+// bakeTarget is one target of docker-bake.hcl, building a version directory
+type bakeTarget struct {
+	Name      string
+	Dir       VersionDir
+	Platforms []string
+	Tags      []string
+	// Contexts maps base images to the targets which build them
+	Contexts map[string]string
+}
+
+// bakeTargetName returns the name of the target for a version directory.
+// Target names may not contain dots, so eg. enterprise/couchbase-server/7.6.2
+// becomes couchbase-server-enterprise-7_6_2.
+func bakeTargetName(dir VersionDir) string {
+	return fmt.Sprintf("%v-%v-%v", dir.Product, dir.Edition, strings.ReplaceAll(dir.Version, ".", "_"))
+}
+
+// bakeGroupName returns the name of the group of a product's targets for
+// one edition
+func bakeGroupName(product Product, edition Edition) string {
+	return fmt.Sprintf("%v-%v", product, edition)
+}
+
+// bakeTargets returns the targets for dirs, grouped by product and
+// edition and in version order within each group
+func bakeTargets(dirs []VersionDir) ([]bakeTarget, error) {
+	sorted, err := sortVersionDirs(dirs)
+	if err != nil {
+		return nil, err
+	}
+	index, err := newTagIndex(dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := map[VersionDir]bool{}
+	for _, dir := range dirs {
+		exists[dir] = true
+	}
+
+	targets := []bakeTarget{}
+	for _, dir := range sorted {
+		variant, err := newVariant(dir.Edition, dir.Product, dir.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", dir, err)
+		}
+		spec, err := variant.spec()
+		if err != nil {
+			return nil, err
+		}
+
+		target := bakeTarget{
+			Name:     bakeTargetName(dir),
+			Dir:      dir,
+			Contexts: map[string]string{},
+		}
+		for _, arch := range variant.Arches {
+			target.Platforms = append(target.Platforms, "linux/"+string(arch))
+		}
+		if target.Tags, err = variant.tags(index); err != nil {
+			return nil, fmt.Errorf("%v: %w", dir, err)
+		}
+
+		// Build the image this one is based on first, if it is in the tree
+		if spec.BasedOn != "" {
+			basedOnDir := VersionDir{dir.Edition, spec.BasedOn, dir.Version}
+			if exists[basedOnDir] {
+				baseImage, err := variant.dockerBaseImage()
+				if err != nil {
+					return nil, fmt.Errorf("%v: %w", dir, err)
+				}
+				target.Contexts[baseImage] = "target:" + bakeTargetName(basedOnDir)
+			}
+		}
+
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// renderBakeFile returns the contents of docker-bake.hcl for dirs. There
+// is a group for each product and edition, a group for each product
+// containing those, and a default group containing every product.
+func renderBakeFile(dirs []VersionDir) (string, error) {
+	targets, err := bakeTargets(dirs)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	out.WriteString("# Generated by generate/generator; do not edit.\n")
+
+	groups := map[string][]string{}
+	products := []string{}
+	productGroups := map[string][]string{}
+	for _, target := range targets {
+		product := string(target.Dir.Product)
+		group := bakeGroupName(target.Dir.Product, target.Dir.Edition)
+		if len(productGroups[product]) == 0 {
+			products = append(products, product)
+		}
+		if len(groups[group]) == 0 {
+			productGroups[product] = append(productGroups[product], group)
+		}
+		groups[group] = append(groups[group], target.Name)
+	}
+
+	writeGroup(&out, "default", products)
+	for _, product := range products {
+		writeGroup(&out, product, productGroups[product])
+		for _, group := range productGroups[product] {
+			writeGroup(&out, group, groups[group])
+		}
+	}
+
+	for _, target := range targets {
+		fmt.Fprintf(&out, "\ntarget %q {\n", target.Name)
+		fmt.Fprintf(&out, "  context = %q\n", target.Dir.String())
+		fmt.Fprintf(&out, "  platforms = %s\n", hclList(target.Platforms))
+		fmt.Fprintf(&out, "  tags = %s\n", hclList(target.Tags))
+		if len(target.Contexts) > 0 {
+			images := []string{}
+			for image := range target.Contexts {
+				images = append(images, image)
+			}
+			sort.Strings(images)
+			out.WriteString("  contexts = {\n")
+			for _, image := range images {
+				fmt.Fprintf(&out, "    %q = %q\n", image, target.Contexts[image])
+			}
+			out.WriteString("  }\n")
+		}
+		out.WriteString("}\n")
+	}
+
+	return out.String(), nil
+}
+
+// writeGroup writes a group, with one target per line so that adding a
+// version shows up as a one-line diff
+func writeGroup(out *strings.Builder, name string, targets []string) {
+	fmt.Fprintf(out, "\ngroup %q {\n  targets = [\n", name)
+	for _, target := range targets {
+		fmt.Fprintf(out, "    %q,\n", target)
+	}
+	out.WriteString("  ]\n}\n")
+}
+
+// hclList formats strings as an HCL list
+func hclList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// writeBakeFile writes docker-bake.hcl for every version directory
+func writeBakeFile() error {
+	contents, err := renderBakeFile(allVersionDirs())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path.Join(baseDir, bakeFilename), []byte(contents), 0644)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Subdirectories of a version directory which are entirely generated, so
+// any extra files in them are also reported as drift
+var generatedSubdirs = []string{"scripts", "config"}
+
+// checkAllDockerfiles renders every version directory into a temporary
+// tree and writes a unified diff of any differences from the committed
+// tree (and docker-bake.hcl) to out. It returns the number of directories
+// or files which differ.
+func checkAllDockerfiles(out io.Writer) (int, error) {
+	tmpDir, err := os.MkdirTemp("", "generate-check")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dirs, err := generatedVersionDirs()
+	if err != nil {
+		return 0, err
+	}
+	diffs := make([]string, len(dirs))
+	errs := runParallel(dirs, func(i int, dir VersionDir) error {
+		variant, err := newVariant(dir.Edition, dir.Product, dir.Version)
+		if err != nil {
+			return err
+		}
+		variant.OutputDir = path.Join(tmpDir, dir.String())
+		if err := os.MkdirAll(variant.OutputDir, 0755); err != nil {
+			return err
+		}
+		if _, err := generateVariant(variant, false); err != nil {
+			return err
+		}
+
+		diffs[i], err = diffVersionDir(path.Join(baseDir, dir.String()), variant.OutputDir, dir.String())
+		return err
+	})
+
+	bakeDiff, err := diffBakeFile()
+	if err != nil {
+		return 0, err
+	}
+	diffs = append(diffs, bakeDiff)
+
+	drifted := 0
+	for _, diff := range diffs {
+		if diff != "" {
+			fmt.Fprint(out, diff)
+			drifted++
+		}
+	}
+
+	if failed := reportFailures(dirs, errs); failed > 0 {
+		return drifted, fmt.Errorf("%d directories could not be generated", failed)
+	}
+	return drifted, nil
+}
+
+// diffVersionDir compares a committed version directory with a freshly
+// generated one, returning a unified diff labelled relative to relDir.
+// Every generated file is compared; files in the committed directory
+// which were not generated are only reported inside generatedSubdirs.
+func diffVersionDir(committedDir, generatedDir, relDir string) (string, error) {
+	files := map[string]bool{}
+	names := []string{}
+	addFiles := func(root string, subdirs ...string) error {
+		for _, subdir := range subdirs {
+			dir := filepath.Join(root, subdir)
+			if ok, err := exists(dir); err != nil || !ok {
+				return err
+			}
+			err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				name, err := filepath.Rel(root, p)
+				if err != nil {
+					return err
+				}
+				if !files[name] {
+					files[name] = true
+					names = append(names, name)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := addFiles(generatedDir, "."); err != nil {
+		return "", err
+	}
+	if err := addFiles(committedDir, generatedSubdirs...); err != nil {
+		return "", err
+	}
+
+	diffs := ""
+	for _, name := range names {
+		committed, committedName, err := readForDiff(committedDir, name, "a/"+path.Join(relDir, name))
+		if err != nil {
+			return "", err
+		}
+		generated, generatedName, err := readForDiff(generatedDir, name, "b/"+path.Join(relDir, name))
+		if err != nil {
+			return "", err
+		}
+		diffs += unifiedDiff(committedName, generatedName, committed, generated)
+	}
+
+	return diffs, nil
+}
+
+// diffBakeFile compares the committed docker-bake.hcl with a freshly
+// rendered one
+func diffBakeFile() (string, error) {
+	generated, err := renderBakeFile(allVersionDirs())
+	if err != nil {
+		return "", err
+	}
+	committed, committedName, err := readForDiff(baseDir, bakeFilename, "a/"+bakeFilename)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff(committedName, "b/"+bakeFilename, committed, generated), nil
+}
+
+// readForDiff returns the contents of dir/name and its label in a diff.
+// Missing files are empty, and labelled /dev/null.
+func readForDiff(dir, name, label string) (string, string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return "", "/dev/null", nil
+	} else if err != nil {
+		return "", "", err
+	}
+	return string(data), label, nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Checksums is the checksum lockfile, generate/checksums.json, which maps
+// product/edition/version/arch (eg. couchbase-server/enterprise/7.6.2/arm64)
+// to the SHA256 of the corresponding package. Generation reads checksums
+// from here, so it is reproducible and works offline; "generate refresh"
+// downloads them from the release server.
+type Checksums map[string]string
+
+var (
+	checksums     Checksums
+	checksumsFile string
+	sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+	// sha256Fetches records the result of downloading each .sha256 URL
+	sha256Fetches      = map[string]*sha256Fetch{}
+	sha256FetchesMutex sync.Mutex
+
+	// allowMissingChecksum permits generating Dockerfiles for packages
+	// whose checksums are unavailable, which then deliberately skip
+	// checksum verification (CB_SKIP_CHECKSUM=true)
+	allowMissingChecksum bool
+)
+
+type sha256Fetch struct {
+	once sync.Once
+	sum  string
+	err  error
+}
+
+// loadChecksums reads the checksum lockfile. A missing file is treated as
+// an empty lockfile.
+func loadChecksums(filename string) (Checksums, error) {
+	sums := Checksums{}
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return sums, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &sums); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	for key, sum := range sums {
+		if !sha256Pattern.MatchString(sum) {
+			return nil, fmt.Errorf("%s: %s: invalid SHA256 %q", filename, key, sum)
+		}
+	}
+
+	return sums, nil
+}
+
+// save writes the checksum lockfile, with keys sorted so that diffs of
+// the file are minimal
+func (sums Checksums) save(filename string) error {
+	data, err := json.MarshalIndent(sums, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// checksumKey returns the lockfile key for this variant's package on arch
+func (variant DockerfileVariant) checksumKey(arch Arch) string {
+	version := variant.Version
+	if variant.IsStaging {
+		version = fmt.Sprintf("%s-staging", version)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", variant.Product, variant.Edition, version, arch)
+}
+
+// getSHA256 returns the checksum of this variant's package on arch, from
+// the lockfile if possible, otherwise from the release server. It is an
+// error if the checksum is unavailable, unless allowMissingChecksum is
+// set, in which case the checksum is empty and skipChecksum() is true.
+// Arches the variant does not support have an empty checksum.
+func (variant DockerfileVariant) getSHA256(arch Arch) (string, error) {
+	if !variant.hasArch(arch) {
+		return "", nil
+	}
+
+	sum, err := variant.lookupSHA256(arch)
+	if err != nil && allowMissingChecksum {
+		log.Printf("WARNING: %v; checksum verification will be skipped", err)
+		return "", nil
+	}
+	return sum, err
+}
+
+// skipChecksum returns true if the Dockerfile must skip checksum
+// verification, because allowMissingChecksum is set and the checksum of
+// at least one package is unavailable
+func (variant DockerfileVariant) skipChecksum() bool {
+	if !allowMissingChecksum {
+		return false
+	}
+	for _, arch := range variant.Arches {
+		if _, err := variant.lookupSHA256(arch); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (variant DockerfileVariant) lookupSHA256(arch Arch) (string, error) {
+	key := variant.checksumKey(arch)
+	if sum, ok := checksums[key]; ok {
+		return sum, nil
+	}
+
+	log.Printf("No checksum for %s in lockfile; run 'generate refresh' to add it", key)
+	sum, err := variant.fetchSHA256(arch)
+	if err != nil {
+		return "", fmt.Errorf("no checksum for %s: %w", key, err)
+	}
+	return sum, nil
+}
+
+// fetchSHA256 downloads the checksum of this variant's package on arch
+// from the release server. Each URL is only downloaded once per run, no
+// matter how many variants ask for it.
+func (variant DockerfileVariant) fetchSHA256(arch Arch) (string, error) {
+	sha256url, err := variant.packageURL(arch)
+	if err != nil {
+		return "", err
+	}
+	sha256url += ".sha256"
+
+	sha256FetchesMutex.Lock()
+	fetch, ok := sha256Fetches[sha256url]
+	if !ok {
+		fetch = &sha256Fetch{}
+		sha256Fetches[sha256url] = fetch
+	}
+	sha256FetchesMutex.Unlock()
+
+	fetch.once.Do(func() {
+		fetch.sum, fetch.err = downloadSHA256(sha256url)
+	})
+	return fetch.sum, fetch.err
+}
+
+func downloadSHA256(sha256url string) (string, error) {
+	log.Print(sha256url)
+
+	body, err := fetcher.Fetch(sha256url)
+	if err != nil {
+		return "", &FetchError{URL: sha256url, Err: err}
+	}
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 || !sha256Pattern.MatchString(fields[0]) {
+		return "", &FetchError{URL: sha256url, Err: fmt.Errorf("no SHA256 found")}
+	}
+	return fields[0], nil
+}
+
+// refreshChecksums downloads the checksum of every package for every
+// version directory of each product which verifies checksums, and
+// updates the lockfile. Entries which cannot be downloaded are left as
+// they were; the number of such failures is returned.
+func refreshChecksums() (int, error) {
+	var mutex sync.Mutex
+	failures := 0
+
+	dirs, err := generatedVersionDirs()
+	if err != nil {
+		return 0, err
+	}
+	errs := runParallel(dirs, func(i int, dir VersionDir) error {
+		variant, err := newVariant(dir.Edition, dir.Product, dir.Version)
+		if err != nil {
+			return err
+		}
+		spec, err := variant.spec()
+		if err != nil {
+			return err
+		}
+		if !spec.Checksums {
+			return nil
+		}
+
+		for _, arch := range variant.Arches {
+			sum, err := variant.fetchSHA256(arch)
+
+			mutex.Lock()
+			if err != nil {
+				log.Printf("Error refreshing %s: %v", variant.checksumKey(arch), err)
+				failures++
+			} else {
+				checksums[variant.checksumKey(arch)] = sum
+			}
+			mutex.Unlock()
+		}
+		return nil
+	})
+	if failed := reportFailures(dirs, errs); failed > 0 {
+		return failures, fmt.Errorf("%d directories could not be refreshed", failed)
+	}
+
+	return failures, checksums.save(checksumsFile)
+}
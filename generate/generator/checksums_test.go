@@ -0,0 +1,48 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestChecksumsLockfile checks that the lockfile holds the checksum of
+// every package the generated directories of the repository need, so
+// that bulk generation and check mode work offline
+func TestChecksumsLockfile(t *testing.T) {
+	defer func(dir string, sums Checksums, f Fetcher, fetches map[string]*sha256Fetch) {
+		baseDir, checksums, fetcher, sha256Fetches = dir, sums, f, fetches
+	}(baseDir, checksums, fetcher, sha256Fetches)
+	baseDir = repoDir
+	sums, err := loadChecksums(filepath.Join(baseDir, "generate", "checksums.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksums = sums
+	// Nothing can be downloaded, and nothing downloaded earlier is reused
+	fetcher = DirFetcher{Dir: t.TempDir()}
+	sha256Fetches = map[string]*sha256Fetch{}
+
+	dirs, err := generatedVersionDirs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range dirs {
+		variant, err := newVariant(dir.Edition, dir.Product, dir.Version)
+		if err != nil {
+			t.Errorf("%v: %v", dir, err)
+			continue
+		}
+		spec, err := variant.spec()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !spec.Checksums || len(spec.PackageFile) == 0 {
+			continue
+		}
+		for _, arch := range variant.Arches {
+			if _, err := variant.getSHA256(arch); err != nil {
+				t.Errorf("no checksum for %s in the lockfile; run 'generate refresh'", variant.checksumKey(arch))
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// A map of "overrides" which specify custom package download urls and package names
+// for unreleased or otherwise special version. These are read from
+// generate/version_customizations.json.
+// Key format: $product_$edition_$version (eg, sync-gateway_community_2.0.0-latestbuild)
+type VersionCustomizations map[string]VersionCustomization
+
+// Parameters that can be customized. Any of these may contain @@ARCH@@,
+// which is replaced by the architecture when a specific one is needed.
+type VersionCustomization struct {
+	// ReleaseUrl replaces the directory packages are downloaded from
+	ReleaseUrl string `json:"release_url,omitempty"`
+	// PackageUrl replaces the full package download URL. It must end in
+	// PackageFilename; if ReleaseUrl is not set, it is the rest of this.
+	PackageUrl string `json:"package_url,omitempty"`
+	// PackageFilename replaces the package filename
+	PackageFilename string `json:"package_filename,omitempty"`
+}
+
+// placeholderPattern matches anything that looks like an @@ARCH@@
+// placeholder, so that misspelled ones are rejected rather than left in
+// download URLs
+var placeholderPattern = regexp.MustCompile(`@@[^@]*@@`)
+
+// loadVersionCustomizations reads and validates the version customizations
+// in filename. A missing file means there are no customizations.
+func loadVersionCustomizations(filename string) (VersionCustomizations, error) {
+	customizations := VersionCustomizations{}
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return customizations, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	// Sort keys so that errors are reported deterministically
+	keys := []string{}
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var customization VersionCustomization
+		decoder := json.NewDecoder(bytes.NewReader(entries[key]))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&customization); err != nil {
+			return nil, fmt.Errorf("%s: %s: %v", filename, key, err)
+		}
+		if err := customization.validate(key); err != nil {
+			return nil, fmt.Errorf("%s: %s: %v", filename, key, err)
+		}
+		customizations[key] = customization
+	}
+
+	return customizations, nil
+}
+
+// validate checks that a customization is well-formed and its key refers
+// to a known product and edition
+func (c VersionCustomization) validate(key string) error {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return fmt.Errorf("key must be of the form PRODUCT_EDITION_VERSION")
+	}
+	if _, ok := registry.product(Product(parts[0])); !ok {
+		return fmt.Errorf("unknown product %q", parts[0])
+	}
+	knownEdition := false
+	for _, edition := range registry.Editions {
+		if Edition(parts[1]) == edition {
+			knownEdition = true
+		}
+	}
+	if !knownEdition {
+		return fmt.Errorf("unknown edition %q", parts[1])
+	}
+	if _, err := ParseVersion(parts[2]); err != nil {
+		return err
+	}
+
+	if c.ReleaseUrl == "" && c.PackageUrl == "" && c.PackageFilename == "" {
+		return fmt.Errorf("no customizations specified")
+	}
+	if c.PackageUrl != "" {
+		if c.PackageFilename == "" {
+			return fmt.Errorf("package_url requires package_filename")
+		}
+		if !strings.HasSuffix(c.PackageUrl, "/"+c.PackageFilename) {
+			return fmt.Errorf("package_url must end in /%s", c.PackageFilename)
+		}
+	}
+	for _, value := range []string{c.ReleaseUrl, c.PackageUrl, c.PackageFilename} {
+		for _, placeholder := range placeholderPattern.FindAllString(value, -1) {
+			if placeholder != string(Archgeneric) {
+				return fmt.Errorf("unknown placeholder %s in %q, only %s is replaced", placeholder, value, Archgeneric)
+			}
+		}
+	}
+	return nil
+}
+
+// releaseURL returns the customized release directory, if any
+func (c VersionCustomization) releaseURL() string {
+	if c.ReleaseUrl != "" {
+		return c.ReleaseUrl
+	}
+	return strings.TrimSuffix(c.PackageUrl, "/"+c.PackageFilename)
+}
+
+func (c VersionCustomization) packageFile(arch Arch) string {
+	return strings.ReplaceAll(c.PackageFilename, string(Archgeneric), string(arch))
+}
+
+func (c VersionCustomization) packageURL(arch Arch) string {
+	return strings.ReplaceAll(c.PackageUrl, string(Archgeneric), string(arch))
+}
+
+func (variant DockerfileVariant) versionCustomization() (v VersionCustomization, exists bool) {
+	// eg, "sync-gateway_community_2.0.0-build
+	key := variant.versionCustomizationKey()
+
+	v, exists = versionCustomizations[key]
+	return v, exists
+}
+
+func (variant DockerfileVariant) versionCustomizationKey() string {
+	return fmt.Sprintf("%s_%s_%s", variant.Product, variant.Edition, variant.Version)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadVersionCustomizations(t *testing.T) {
+	const devbuild = `"package_url": "http://cbmobile-packages.s3.amazonaws.com/sg_2.0.0-827_@@ARCH@@.rpm", "package_filename": "sg_2.0.0-827_@@ARCH@@.rpm"`
+
+	tests := []struct {
+		name string
+		json string
+		// err is a substring of the expected error, or empty if the
+		// customizations are valid
+		err string
+	}{
+		{"valid", `{"sync-gateway_community_2.0.0-devbuild": {` + devbuild + `}}`, ""},
+		{"release url only", `{"couchbase-server_enterprise_7.6.2": {"release_url": "https://example.com/7.6.2"}}`, ""},
+		{"unknown field", `{"sync-gateway_community_2.0.0-devbuild": {"package_uri": "http://example.com/sg.rpm"}}`, "unknown field"},
+		{"unknown product", `{"sync-gatway_community_2.0.0": {` + devbuild + `}}`, `unknown product "sync-gatway"`},
+		{"unknown edition", `{"sync-gateway_communty_2.0.0": {` + devbuild + `}}`, `unknown edition "communty"`},
+		{"missing version", `{"sync-gateway_community": {` + devbuild + `}}`, "PRODUCT_EDITION_VERSION"},
+		{"bad version", `{"sync-gateway_community_2.x.0": {` + devbuild + `}}`, "2.x.0"},
+		{"bad version suffix", `{"sync-gateway_community_2.0.0-dev+build": {` + devbuild + `}}`, "2.0.0-dev+build"},
+		{"empty", `{"sync-gateway_community_2.0.0": {}}`, "no customizations"},
+		{"url without filename", `{"sync-gateway_community_2.0.0": {"package_url": "http://example.com/sg.rpm"}}`, "requires package_filename"},
+		{"url and filename differ", `{"sync-gateway_community_2.0.0": {"package_url": "http://example.com/sg.rpm", "package_filename": "sg.deb"}}`, "must end in /sg.deb"},
+		{"misspelled arch", `{"sync-gateway_community_2.0.0": {"package_url": "http://example.com/sg_@@arch@@.rpm", "package_filename": "sg_@@arch@@.rpm"}}`, "unknown placeholder @@arch@@"},
+		{"unknown arch placeholder", `{"couchbase-server_enterprise_7.6.2": {"release_url": "https://example.com/@@ARM64@@"}}`, "unknown placeholder @@ARM64@@"},
+	}
+	for _, test := range tests {
+		filename := filepath.Join(t.TempDir(), "version_customizations.json")
+		if err := os.WriteFile(filename, []byte(test.json), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := loadVersionCustomizations(filename)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %v", test.name, err)
+		case test.err != "" && err == nil:
+			t.Errorf("%s: was accepted", test.name)
+		case test.err != "" && !strings.Contains(err.Error(), test.err):
+			t.Errorf("%s: error %q does not mention %q", test.name, err, test.err)
+		}
+	}
+
+	customizations, err := loadVersionCustomizations(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || len(customizations) != 0 {
+		t.Errorf("missing file: got %v, %v", customizations, err)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numberedLines returns lines "1\n" to "n\n", with the given line numbers
+// replaced by "changed"
+func numberedLines(n int, changed ...int) string {
+	var out strings.Builder
+	for i := 1; i <= n; i++ {
+		line := fmt.Sprint(i)
+		for _, c := range changed {
+			if i == c {
+				line = "changed"
+			}
+		}
+		out.WriteString(line + "\n")
+	}
+	return out.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"identical", "a\nb\n", "a\nb\n", ""},
+		{
+			"longest common subsequence",
+			"a\nb\nc\nd\n", "b\nx\nd\ne\n",
+			"@@ -1,4 +1,4 @@\n-a\n b\n-c\n+x\n d\n+e\n",
+		},
+		{
+			"changes six lines apart share a hunk",
+			numberedLines(20), numberedLines(20, 5, 12),
+			"@@ -2,14 +2,14 @@\n 2\n 3\n 4\n-5\n+changed\n 6\n 7\n 8\n 9\n 10\n 11\n-12\n+changed\n 13\n 14\n 15\n",
+		},
+		{
+			"changes seven lines apart have separate hunks",
+			numberedLines(20), numberedLines(20, 5, 13),
+			"@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+changed\n 6\n 7\n 8\n" +
+				"@@ -10,7 +10,7 @@\n 10\n 11\n 12\n-13\n+changed\n 14\n 15\n 16\n",
+		},
+		{
+			"context is cut short at the start and end",
+			numberedLines(5), numberedLines(5, 1, 5),
+			"@@ -1,5 +1,5 @@\n-1\n+changed\n 2\n 3\n 4\n-5\n+changed\n",
+		},
+		{
+			"added file",
+			"", "a\nb\n",
+			"@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			"removed file",
+			"a\nb\n", "",
+			"@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			"no newline at end of file",
+			"a\nb", "a\nc",
+			"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+		{
+			"newline added at end of file",
+			"a\nb", "a\nb\n",
+			"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+	for _, test := range tests {
+		want := test.want
+		if want != "" {
+			want = "--- a\n+++ b\n" + want
+		}
+		if got := unifiedDiff("a", "b", test.a, test.b); got != want {
+			t.Errorf("%s:\ngot:\n%s\nwant:\n%s", test.name, got, want)
+		}
+	}
+}
+
+func TestUnifiedDiffLimit(t *testing.T) {
+	defer func(limit int) { maxDiffCells = limit }(maxDiffCells)
+	maxDiffCells = 16
+
+	// The common prefix and suffix are kept, and the middle, which is too
+	// large to diff line by line, is replaced outright
+	got := unifiedDiff("a", "b", numberedLines(10), numberedLines(10, 3, 5, 7))
+	want := "--- a\n+++ b\n@@ -1,10 +1,10 @@\n 1\n 2\n" +
+		"-3\n-4\n-5\n-6\n-7\n+changed\n+4\n+changed\n+6\n+changed\n" +
+		" 8\n 9\n 10\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// A small middle is still diffed line by line
+	got = unifiedDiff("a", "b", numberedLines(10), numberedLines(10, 5))
+	if !strings.Contains(got, " 4\n-5\n+changed\n 6\n") {
+		t.Errorf("got:\n%s", got)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// releaseIndexLink matches the links of a directory listing
+var releaseIndexLink = regexp.MustCompile(`(?i)<a\s[^>]*href="([^"]+)"`)
+
+// releaseIndexURL returns the URL of the product's release index
+func (spec *ProductSpec) releaseIndexURL() (string, error) {
+	releaseHost := releaseBase
+	if releaseHost == "" {
+		releaseHost = spec.releaseHosts().Production
+	}
+	funcs := template.FuncMap{
+		"releaseHost": func() string { return releaseHost },
+		"product":     func() Product { return spec.Name },
+	}
+
+	tmpl, err := template.New("releaseIndex").Funcs(funcs).Parse(spec.ReleaseIndex.URL)
+	if err != nil {
+		return "", fmt.Errorf("product %v: releaseIndex: %v", spec.Name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return "", fmt.Errorf("product %v: releaseIndex: %v", spec.Name, err)
+	}
+	return buf.String(), nil
+}
+
+// parseReleaseIndex returns the versions listed in a release index of the
+// given format. Entries which are not versions, such as the parent
+// directory or other products' directories, are ignored.
+func parseReleaseIndex(data []byte, format string) ([]ProductVersion, error) {
+	names := []string{}
+	switch format {
+	case "", "html":
+		for _, match := range releaseIndexLink.FindAllSubmatch(data, -1) {
+			href := html.UnescapeString(string(match[1]))
+			names = append(names, path.Base(strings.TrimSuffix(href, "/")))
+		}
+	case "json":
+		if err := json.Unmarshal(data, &names); err != nil {
+			return nil, fmt.Errorf("bad release index: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown release index format %q", format)
+	}
+
+	seen := map[string]bool{}
+	versions := []ProductVersion{}
+	for _, name := range names {
+		v, err := ParseVersion(name)
+		if err != nil || v.Staging || seen[v.String()] {
+			continue
+		}
+		seen[v.String()] = true
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// discoverReleases reads the release index of every product selected by
+// filter and returns a directory for each published version which has
+// none yet, in each selected edition, provided its packages (and
+// checksums) are published for every arch. Unless filter.Versions is set,
+// versions older than a product's oldest directory are not considered.
+func discoverReleases(filter VersionDirFilter) ([]VersionDir, error) {
+	existing := allVersionDirs()
+	discovered := []VersionDir{}
+
+	for _, spec := range registry.Products {
+		if filter.Product != "" && spec.Name != filter.Product {
+			continue
+		}
+		if spec.ReleaseIndex == nil {
+			continue
+		}
+
+		indexURL, err := spec.releaseIndexURL()
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Reading %s", indexURL)
+		data, err := fetcher.Fetch(indexURL)
+		if err != nil {
+			return nil, &FetchError{URL: indexURL, Err: err}
+		}
+		published, err := parseReleaseIndex(data, spec.ReleaseIndex.Format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", indexURL, err)
+		}
+
+		for _, edition := range spec.editions() {
+			if filter.Edition != "" && edition != filter.Edition {
+				continue
+			}
+			dirs, err := spec.undiscovered(edition, published, existing, filter.Versions)
+			if err != nil {
+				return nil, err
+			}
+			discovered = append(discovered, dirs...)
+		}
+	}
+
+	return sortVersionDirs(discovered)
+}
+
+// undiscovered returns the directories of the versions among published
+// which edition has no directory for among existing, and whose packages
+// are all published
+func (spec *ProductSpec) undiscovered(edition Edition, published []ProductVersion, existing []VersionDir, versions VersionConstraint) ([]VersionDir, error) {
+	// A directory accounts for its own version and the version it is an
+	// alias of, eg. 7.0.3 for 7.0.3-MP1
+	known := map[string]bool{}
+	var oldest *ProductVersion
+	for _, dir := range existing {
+		if dir.Edition != edition || dir.Product != spec.Name {
+			continue
+		}
+		v, err := ParseVersion(dir.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", dir, err)
+		}
+		if v.Staging {
+			continue
+		}
+		known[dir.Version] = true
+		if alias, ok := spec.VersionAliases[dir.Version]; ok {
+			known[alias] = true
+		}
+		if oldest == nil || v.Compare(*oldest) < 0 {
+			oldest = &v
+		}
+	}
+
+	dirs := []VersionDir{}
+	for _, v := range published {
+		if known[v.String()] {
+			continue
+		}
+		if versions != nil {
+			if !versions.Check(v) {
+				continue
+			}
+		} else if oldest != nil && v.Compare(*oldest) < 0 {
+			continue
+		}
+
+		dir := VersionDir{edition, spec.Name, v.String()}
+		reason, excluded, err := dir.excluded()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", dir, err)
+		}
+		if excluded {
+			log.Printf("Skipping %v: %s", dir, reason)
+			continue
+		}
+		variant, err := newVariant(edition, spec.Name, v.String())
+		if err != nil {
+			log.Printf("Skipping %v: %v", dir, err)
+			continue
+		}
+		dirExists, err := exists(variant.targetDir())
+		if err != nil {
+			return nil, err
+		}
+		if dirExists {
+			continue
+		}
+		if err := variant.verifyPackages(Checksums{}); err != nil {
+			log.Printf("Skipping %v: %v", dir, err)
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// scaffoldReleases adds each of dirs as a new release, as the new command
+// does, returning the tags of the new images
+func scaffoldReleases(dirs []VersionDir) ([]string, error) {
+	tags := []string{}
+	for _, dir := range dirs {
+		dirTags, err := newRelease(dir.Product, dir.Version, dir.Edition)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", dir, err)
+		}
+		tags = append(tags, dirTags...)
+	}
+	return tags, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseReleaseIndex(t *testing.T) {
+	tests := []struct {
+		filename string
+		format   string
+		want     []string
+	}{
+		{"releases.html", "html", []string{"7.6.1", "7.6.2", "7.0.3-MP1", "8.0.0-beta", "8.0.0"}},
+		{"edge-server.json", "json", []string{"1.0.0", "1.0.1", "1.1.0"}},
+	}
+	for _, test := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", "index", test.filename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions, err := parseReleaseIndex(data, test.format)
+		if err != nil {
+			t.Errorf("%s: %v", test.filename, err)
+			continue
+		}
+		got := []string{}
+		for _, v := range versions {
+			got = append(got, v.String())
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: versions = %q, want %q", test.filename, got, test.want)
+		}
+	}
+}
+
+func TestDiscoverReleases(t *testing.T) {
+	tree, packages := withScratchTree(t)
+	for _, ver := range []string{"1.0.0", "1.1.0"} {
+		if err := os.MkdirAll(filepath.Join(tree, "enterprise", "couchbase-edge-server", ver), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 0.9.0 predates the oldest directory, 1.1.1 has no package, and
+	// 1.0.0 and 1.1.0 already have directories
+	for _, ver := range []string{"0.9.0", "1.0.0", "1.0.1", "1.1.0", "1.2.0"} {
+		publish(t, packages, "releases/couchbase-edge-server/"+ver+"/couchbase-edge-server_"+ver+"_amd64.deb")
+	}
+	if err := os.MkdirAll(filepath.Join(packages, "releases", "couchbase-edge-server", "1.1.1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	filter := VersionDirFilter{Product: "couchbase-edge-server"}
+	dirs, err := discoverReleases(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []VersionDir{
+		{EditionEnterprise, "couchbase-edge-server", "1.0.1"},
+		{EditionEnterprise, "couchbase-edge-server", "1.2.0"},
+	}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("discovered %v, want %v", dirs, want)
+	}
+
+	if filter.Versions, err = ParseConstraint("< 1.0"); err != nil {
+		t.Fatal(err)
+	}
+	dirs, err = discoverReleases(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []VersionDir{{EditionEnterprise, "couchbase-edge-server", "0.9.0"}}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("discovered %v with --versions, want %v", dirs, want)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import "fmt"
+
+// The errors below are returned (possibly wrapped) by the generation
+// pipeline, so that main and the tests can use errors.As to tell failures
+// apart. The pipeline is still part of this command, driven by its
+// globals (registry, fetcher, checksums, ...), rather than a package
+// other tools can import.
+
+// UnknownProductError means a product is not in the product registry
+type UnknownProductError struct {
+	Product Product
+}
+
+func (e *UnknownProductError) Error() string {
+	return fmt.Sprintf("unknown product %q", e.Product)
+}
+
+// BadVersionError means a version string could not be parsed
+type BadVersionError struct {
+	Version string
+	Err     error
+}
+
+func (e *BadVersionError) Error() string {
+	return fmt.Sprintf("bad version %q: %v", e.Version, e.Err)
+}
+
+func (e *BadVersionError) Unwrap() error {
+	return e.Err
+}
+
+// MissingTemplateError means no template applies to a variant, or the
+// template file it names cannot be read
+type MissingTemplateError struct {
+	Product Product
+	Version string
+	// Path is the template file, if one was selected
+	Path string
+	Err  error
+}
+
+func (e *MissingTemplateError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("no template for %v %v", e.Product, e.Version)
+	}
+	return fmt.Sprintf("template for %v %v: %v", e.Product, e.Version, e.Err)
+}
+
+func (e *MissingTemplateError) Unwrap() error {
+	return e.Err
+}
+
+// FetchError means something could not be downloaded from the package
+// server
+type FetchError struct {
+	URL string
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("downloading %s: %v", e.URL, e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// ExcludedError means a version directory was asked for explicitly, but
+// is frozen or excluded by its product's exclusions
+type ExcludedError struct {
+	Dir    VersionDir
+	Reason string
+}
+
+func (e *ExcludedError) Error() string {
+	return fmt.Sprintf("%v is excluded from generation (%s)", e.Dir, e.Reason)
+}
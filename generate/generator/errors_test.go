@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateErrorTypes(t *testing.T) {
+	tree, _ := withScratchTree(t)
+	dir := filepath.Join(tree, "enterprise", "server-sandbox", "7.6.2")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	settings := VersionSettings{Template: "Dockerfile.missing"}
+	if err := settings.save(filepath.Join(dir, settingsFilename)); err != nil {
+		t.Fatal(err)
+	}
+
+	err := generateOneDockerfile(EditionEnterprise, "couchbase-nope", "7.6.2", t.TempDir(), nil, false, false)
+	var unknownProduct *UnknownProductError
+	if !errors.As(err, &unknownProduct) || unknownProduct.Product != "couchbase-nope" {
+		t.Errorf("unknown product: got error %v", err)
+	}
+
+	err = generateOneDockerfile(EditionEnterprise, "server-sandbox", "7.6.x", t.TempDir(), nil, false, false)
+	var badVersion *BadVersionError
+	if !errors.As(err, &badVersion) || badVersion.Version != "7.6.x" {
+		t.Errorf("bad version: got error %v", err)
+	}
+
+	err = generateOneDockerfile(EditionEnterprise, "server-sandbox", "7.6.2", dir, nil, false, false)
+	var missingTemplate *MissingTemplateError
+	if !errors.As(err, &missingTemplate) || filepath.Base(missingTemplate.Path) != "Dockerfile.missing" {
+		t.Errorf("missing template: got error %v", err)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing template error does not wrap the read error: %v", err)
+	}
+}
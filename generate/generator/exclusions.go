@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// frozenMarker is the name of a file which, placed in a version
+// directory, keeps the generator from touching it. Its contents are the
+// reason, eg. that the directory is maintained by hand.
+const frozenMarker = ".frozen"
+
+// Exclusion keeps the version directories of a product which satisfy
+// the (optional) Versions constraint and match the (optional) Match
+// regular expression from being generated, checked or refreshed, eg.
+// because they are no longer maintained. Reason is logged whenever a
+// directory is skipped.
+type Exclusion struct {
+	Rule
+	Reason string `json:"reason"`
+}
+
+type Exclusions []*Exclusion
+
+func (exclusions Exclusions) compile() error {
+	for _, exclusion := range exclusions {
+		if exclusion.Versions == "" && exclusion.Match == "" {
+			return fmt.Errorf("exclusion %q applies to every version", exclusion.Reason)
+		}
+		if exclusion.Reason == "" {
+			return fmt.Errorf("exclusion with no reason")
+		}
+		if err := (Rules{&exclusion.Rule}).compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excluded returns the reason the generator should leave dir alone, if
+// it is frozen by a marker file or excluded by its product's exclusions
+func (dir VersionDir) excluded() (string, bool, error) {
+	marker, err := os.ReadFile(path.Join(baseDir, dir.String(), frozenMarker))
+	if err == nil {
+		reason := strings.TrimSpace(string(marker))
+		if reason == "" {
+			reason = "frozen"
+		}
+		return reason, true, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", false, err
+	}
+
+	spec, ok := registry.product(dir.Product)
+	if !ok {
+		return "", false, &UnknownProductError{Product: dir.Product}
+	}
+	for _, exclusion := range spec.Exclusions {
+		ok, err := exclusion.matches(dir.Version)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			return exclusion.Reason, true, nil
+		}
+	}
+	return "", false, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExcluded(t *testing.T) {
+	tests := []struct {
+		dir    VersionDir
+		reason string
+	}{
+		{VersionDir{EditionCommunity, "sync-gateway", "1.5.0"}, "no longer maintained"},
+		{VersionDir{EditionCommunity, "sync-gateway", "2.0.0-devbuild"}, "no longer maintained"},
+		{VersionDir{EditionCommunity, "sync-gateway", "1.1.0-forestdb_bucket"}, "ForestDB"},
+		{VersionDir{EditionEnterprise, "couchbase-server", "7.0.0-5017"}, "build 5017"},
+		{VersionDir{EditionEnterprise, "sync-gateway", "2.1.0"}, ""},
+		{VersionDir{EditionEnterprise, "couchbase-server", "7.0.0"}, ""},
+	}
+	for _, test := range tests {
+		reason, excluded, err := test.dir.excluded()
+		if err != nil {
+			t.Errorf("%v: %v", test.dir, err)
+			continue
+		}
+		if excluded != (test.reason != "") {
+			t.Errorf("%v: excluded = %v, want %v", test.dir, excluded, !excluded)
+			continue
+		}
+		if !strings.Contains(reason, test.reason) {
+			t.Errorf("%v: reason %q does not mention %q", test.dir, reason, test.reason)
+		}
+	}
+}
+
+func TestExclusionsCompile(t *testing.T) {
+	bad := []Exclusions{
+		{{Reason: "everything"}},
+		{{Rule: Rule{Versions: "< 2.1"}}},
+		{{Rule: Rule{Versions: "~> 2.1"}, Reason: "bad constraint"}},
+	}
+	for _, exclusions := range bad {
+		if err := exclusions.compile(); err == nil {
+			t.Errorf("compile(%+v) succeeded", *exclusions[0])
+		}
+	}
+}
+
+func TestGenerateExcluded(t *testing.T) {
+	tree, _ := withScratchTree(t)
+	frozen := VersionDir{EditionEnterprise, "couchbase-server", "7.0.0-5017"}
+	dir := filepath.Join(tree, frozen.String())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, frozenMarker), []byte("maintained by hand\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, arch := range []Arch{Archamd64, Archarm64} {
+		checksums["couchbase-server/enterprise/7.0.0-5017/"+string(arch)] = strings.Repeat("0", 64)
+	}
+
+	// Single mode refuses a frozen directory, unless forced
+	err := generateOneDockerfile(frozen.Edition, frozen.Product, frozen.Version, dir, nil, false, false)
+	var excludedErr *ExcludedError
+	if !errors.As(err, &excludedErr) || excludedErr.Reason != "maintained by hand" {
+		t.Errorf("frozen directory: got error %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Dockerfile")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("frozen directory was generated: %v", err)
+	}
+	if err := generateOneDockerfile(frozen.Edition, frozen.Product, frozen.Version, dir, nil, false, true); err != nil {
+		t.Errorf("forced: %v", err)
+	}
+
+	// Bulk mode skips it
+	dirs, err := generatedVersionDirs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 0 {
+		t.Errorf("generated %v", dirs)
+	}
+
+	// A marker which cannot be read is an error, rather than being
+	// ignored
+	if err := os.Remove(filepath.Join(dir, frozenMarker)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, frozenMarker), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if dirs, err := generatedVersionDirs(); err == nil {
+		t.Errorf("unreadable marker: got %v", dirs)
+	}
+	if err := generateOneDockerfile(frozen.Edition, frozen.Product, frozen.Version, dir, nil, false, true); err == nil {
+		t.Errorf("unreadable marker: single mode succeeded")
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Fetcher retrieves the contents of a package-server URL, such as a
+// package's .sha256 file. Head checks that a URL exists, without
+// downloading it.
+type Fetcher interface {
+	Fetch(url string) ([]byte, error)
+	Head(url string) error
+}
+
+// HTTPFetcher fetches http:// and https:// URLs
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+// FileFetcher fetches file:// URLs from the local filesystem
+type FileFetcher struct{}
+
+// DirFetcher ignores the scheme and host of every URL and reads the
+// corresponding path under Dir instead, eg. with Dir "testdata/packages",
+// https://packages.couchbase.com/releases/7.6.2/x.sha256 is read from
+// testdata/packages/releases/7.6.2/x.sha256. This is useful for mirrors
+// and test fixtures laid out like the release server.
+type DirFetcher struct {
+	Dir string
+}
+
+// SchemeFetcher dispatches to a Fetcher according to the URL's scheme
+type SchemeFetcher map[string]Fetcher
+
+// fetcher is used for all package-server access
+var fetcher Fetcher = defaultFetcher()
+
+func defaultFetcher() Fetcher {
+	httpFetcher := HTTPFetcher{Client: http.DefaultClient}
+	return SchemeFetcher{
+		"http":  httpFetcher,
+		"https": httpFetcher,
+		"file":  FileFetcher{},
+	}
+}
+
+func (f HTTPFetcher) Fetch(rawurl string) ([]byte, error) {
+	resp, err := f.Client.Get(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP status %s", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (f HTTPFetcher) Head(rawurl string) error {
+	resp, err := f.Client.Head(rawurl)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP status %s", resp.Status)
+	}
+	return nil
+}
+
+func (f FileFetcher) Fetch(rawurl string) ([]byte, error) {
+	filename, err := f.filename(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return readFileOrListing(filename)
+}
+
+func (f FileFetcher) Head(rawurl string) error {
+	filename, err := f.filename(rawurl)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(filename)
+	return err
+}
+
+func (f FileFetcher) filename(rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", fmt.Errorf("%s: remote file URLs are not supported", rawurl)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
+
+func (f DirFetcher) Fetch(rawurl string) ([]byte, error) {
+	filename, err := f.filename(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return readFileOrListing(filename)
+}
+
+func (f DirFetcher) Head(rawurl string) error {
+	filename, err := f.filename(rawurl)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stat(filename)
+	return err
+}
+
+// filename returns the file under Dir for rawurl. URLs whose path would
+// lead outside Dir, through "..", are rejected.
+func (f DirFetcher) filename(rawurl string) (string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+	rel := path.Clean(strings.TrimLeft(u.Path, "/"))
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("%s: path is outside %s", rawurl, f.Dir)
+	}
+	return filepath.Join(f.Dir, filepath.FromSlash(rel)), nil
+}
+
+// readFileOrListing reads a file or, for a directory, renders an HTML
+// listing of it like a web server's, so that release indexes can be read
+// from a local mirror
+func readFileOrListing(filename string) ([]byte, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return os.ReadFile(filename)
+	}
+
+	entries, err := os.ReadDir(filename)
+	if err != nil {
+		return nil, err
+	}
+	var listing bytes.Buffer
+	listing.WriteString("<html><body>\n")
+	for _, entry := range entries {
+		name := html.EscapeString(entry.Name())
+		if entry.IsDir() {
+			name += "/"
+		}
+		fmt.Fprintf(&listing, "<a href=\"%s\">%s</a>\n", name, name)
+	}
+	listing.WriteString("</body></html>\n")
+	return listing.Bytes(), nil
+}
+
+func (f SchemeFetcher) Fetch(rawurl string) ([]byte, error) {
+	schemeFetcher, err := f.fetcher(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return schemeFetcher.Fetch(rawurl)
+}
+
+func (f SchemeFetcher) Head(rawurl string) error {
+	schemeFetcher, err := f.fetcher(rawurl)
+	if err != nil {
+		return err
+	}
+	return schemeFetcher.Head(rawurl)
+}
+
+// fetcher returns the Fetcher for rawurl's scheme
+func (f SchemeFetcher) fetcher(rawurl string) (Fetcher, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	schemeFetcher, ok := f[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported URL scheme %q", rawurl, u.Scheme)
+	}
+	return schemeFetcher, nil
+}
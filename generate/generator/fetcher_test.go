@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirFetcher(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "packages")
+	if err := os.MkdirAll(filepath.Join(dir, "releases", "7.6.2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "releases", "7.6.2", "x.sha256"), []byte("inside"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "secret"), []byte("outside"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := DirFetcher{Dir: dir}
+
+	for _, rawurl := range []string{
+		"https://packages.couchbase.com/releases/7.6.2/x.sha256",
+		"https://packages.couchbase.com/releases/7.6.1/../7.6.2/x.sha256",
+		"https://packages.couchbase.com//releases/7.6.2/x.sha256",
+	} {
+		data, err := f.Fetch(rawurl)
+		if err != nil || string(data) != "inside" {
+			t.Errorf("%s: got %q, %v", rawurl, data, err)
+		}
+		if err := f.Head(rawurl); err != nil {
+			t.Errorf("%s: %v", rawurl, err)
+		}
+	}
+
+	for _, rawurl := range []string{
+		"https://packages.couchbase.com/../secret",
+		"https://packages.couchbase.com/releases/../../secret",
+		"https://packages.couchbase.com/releases/%2e%2e/%2e%2e/secret",
+		"file:///../secret",
+	} {
+		data, err := f.Fetch(rawurl)
+		if err == nil || !strings.Contains(err.Error(), "outside") {
+			t.Errorf("%s: got %q, %v", rawurl, data, err)
+		}
+		if err := f.Head(rawurl); err == nil {
+			t.Errorf("%s: Head succeeded", rawurl)
+		}
+	}
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	// Release 7.6.2 is served through a redirect to a mirror, except for
+	// arm64, which is missing; 7.6.1 fails and 7.6.0 has a bad checksum
+	const sum = "5cefdbf8970a86b7869b3bc1f37bea2454e0d1f72733be39a1c20bb5c2641987"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "arm64"):
+			http.NotFound(w, r)
+		case strings.HasPrefix(r.URL.Path, "/7.6.2/"):
+			http.Redirect(w, r, "/mirror"+r.URL.Path, http.StatusFound)
+		case strings.HasPrefix(r.URL.Path, "/mirror/7.6.2/"):
+			if strings.HasSuffix(r.URL.Path, ".sha256") {
+				fmt.Fprintf(w, "%s  package.deb\n", sum)
+			}
+		case strings.HasPrefix(r.URL.Path, "/7.6.1/"):
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		default:
+			fmt.Fprintln(w, "<html>not a checksum</html>")
+		}
+	}))
+	defer server.Close()
+
+	defer func(base string, f Fetcher) { releaseBase, fetcher = base, f }(releaseBase, fetcher)
+	releaseBase = server.URL
+	fetcher = defaultFetcher()
+
+	tests := []struct {
+		version string
+		arch    Arch
+		// err is a substring of the expected error, or empty if sum is
+		// expected
+		err string
+		// published is whether Head finds the package
+		published bool
+	}{
+		{"7.6.2", Archamd64, "", true},
+		{"7.6.2", Archarm64, "404 Not Found", false},
+		{"7.6.1", Archamd64, "500 Internal Server Error", false},
+		{"7.6.0", Archamd64, "no SHA256 found", true},
+	}
+	for _, test := range tests {
+		variant, err := newVariant(EditionEnterprise, "couchbase-server", test.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packageURL, err := variant.packageURL(test.arch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(packageURL, server.URL+"/") {
+			t.Errorf("%s: release base was not applied", packageURL)
+		}
+
+		got, err := variant.fetchSHA256(test.arch)
+		var fetchErr *FetchError
+		switch {
+		case test.err == "" && (err != nil || got != sum):
+			t.Errorf("%s: got %q, %v", packageURL, got, err)
+		case test.err != "" && !errors.As(err, &fetchErr):
+			t.Errorf("%s: got %q, %v, want a FetchError", packageURL, got, err)
+		case test.err != "" && !strings.Contains(err.Error(), test.err):
+			t.Errorf("%s: error %q does not mention %q", packageURL, err, test.err)
+		}
+
+		if err := fetcher.Head(packageURL); test.published != (err == nil) {
+			t.Errorf("Head(%s) = %v", packageURL, err)
+		}
+	}
+}
@@ -1,23 +1,23 @@
-//go:generate go run generate.go "../.."
+//go:generate go run . "../.."
 
 package main
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
-	"net/http"
 	"os"
 	"path"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
 
 	"github.com/docopt/docopt-go"
-	"github.com/hashicorp/go-version"
 )
 
 // Convert Dockerfile.template into version specific Dockerfile
@@ -30,17 +30,9 @@ const (
 	EditionCommunity  = Edition("community")
 )
 
+// Products are described by the registry in generate/products.json
 type Product string
 
-const (
-	ProductServer              = Product("couchbase-server")
-	ProductSyncGw              = Product("sync-gateway")
-	ProductSandbox             = Product("server-sandbox")
-	ProductColumnar            = Product("couchbase-columnar")
-	ProductEdgeServer          = Product("couchbase-edge-server")
-	ProductEnterpriseAnalytics = Product("enterprise-analytics")
-)
-
 // These are Docker's idea of architecture names, eg. amd64, arm64.
 // "Archgeneric" is for a filename with @@ARCH@@ in place of the
 // actual architecture, which will be substituted at build time in
@@ -53,85 +45,86 @@ const (
 	Archgeneric = Arch("@@ARCH@@")
 )
 
-// A map of "overrides" which specify custom package download urls and package names
-// for unreleased or otherwise special version.
-// Key format: $product_$edition_$version (eg, sync-gateway_community_2.0.0-latestbuild)
-// Note: currently only implemented for sync gateway
-type VersionCustomizations map[string]VersionCustomization
-
-// Parameters that can be customized
-type VersionCustomization struct {
-	PackageUrl      string `json:"package_url"`
-	PackageFilename string `json:"package_filename"`
-}
-
-// ProductVersionFilter is a map of Product to a regular expression that should match versions
-// This can be used to exclude older versions from being updated.
-// For an example of usage, see the Sync Gateway entries in init()
-type ProductVersionFilter map[Product]*regexp.Regexp
-
-// Matches returns true if the given product/version matched by the given filter
-func (filter ProductVersionFilter) Matches(product Product, version string) bool {
-	if r := filter[product]; r != nil && r.MatchString(version) {
-		return true
-	}
-	return false
-}
-
 var (
-	default_editions      []Edition
-	default_products      []Product
 	versionCustomizations VersionCustomizations
 	baseDir               string
-	skipGeneration        ProductVersionFilter
+	// releaseBase, if set, replaces the release hosts of every product,
+	// eg. to download from an internal mirror
+	releaseBase string
 )
 
-func init() {
-	default_editions = []Edition{
-		EditionCommunity,
-		EditionEnterprise,
-	}
-
-	default_products = []Product{
-		ProductServer,
-		ProductSyncGw,
-		ProductSandbox,
-		ProductColumnar,
-		ProductEdgeServer,
-		ProductEnterpriseAnalytics,
-	}
-
-	// TODO: Read the version_customizations.json file into map
-	versionCustomizations = map[string]VersionCustomization{}
-	versionCustomizations["sync-gateway_community_2.0.0-devbuild"] = VersionCustomization{
-		PackageUrl:      "http://cbmobile-packages.s3.amazonaws.com/couchbase-sync-gateway-community_2.0.0-827_x86_64.rpm",
-		PackageFilename: "couchbase-sync-gateway-community_2.0.0-827_x86_64.rpm",
-	}
-	versionCustomizations["sync-gateway_enterprise_2.0.0-devbuild"] = VersionCustomization{
-		PackageUrl:      "http://cbmobile-packages.s3.amazonaws.com/couchbase-sync-gateway-enterprise_2.0.0-827_x86_64.rpm",
-		PackageFilename: "couchbase-sync-gateway-enterprise_2.0.0-827_x86_64.rpm",
-	}
-
-	skipGeneration = ProductVersionFilter{
-		ProductSyncGw: regexp.MustCompile(`^(1\.|2\.0\.).+$`), // 1.x and 2.0.x
-	}
-}
-
 func main() {
 	usage := `Dockerfile Generator
 
 Usage:
+  generate check BASE_DIRECTORY [ --allow-missing-checksum ] [ --jobs N ]
+           [ --release-base URL ] [ --package-dir DIR ]
+  generate refresh BASE_DIRECTORY [ --jobs N ]
+           [ --release-base URL ] [ --package-dir DIR ]
+  generate library BASE_DIRECTORY [ -p PRODUCT ] [ -o DIR ]
+  generate list BASE_DIRECTORY [ -p PRODUCT ] [ -e EDITION ]
+           [ --versions CONSTRAINT ] [ --json ]
+  generate new BASE_DIRECTORY -p PRODUCT -v VERSION [ -e EDITION ]
+           [ --allow-missing-checksum ] [ --release-base URL ] [ --package-dir DIR ]
+  generate promote BASE_DIRECTORY -p PRODUCT -v VERSION [ -e EDITION ]
+           [ --release-base URL ] [ --package-dir DIR ]
+  generate discover BASE_DIRECTORY [ -p PRODUCT ] [ -e EDITION ]
+           [ --versions CONSTRAINT ] [ --scaffold ] [ --allow-missing-checksum ]
+           [ --release-base URL ] [ --package-dir DIR ]
   generate BASE_DIRECTORY -p PRODUCT -v VERSION -e EDITION -o DIR [ -t TEMPLATE_ARG ]...
-  generate BASE_DIRECTORY
-
-The first form generates a single Dockerfile and its associated resources
-in the specified directory (which must exist). The second form will
-search for directories under the specified directory with the form
-
-    EDITION/PRODUCT/VERSION
-
-and for each such directory that does not contain a Dockerfile, will
-create the corresponding Dockerfile with its associated resources.
+           [ --values FILE ] [ --force ]
+           [ --allow-missing-checksum ] [ --release-base URL ] [ --package-dir DIR ]
+  generate BASE_DIRECTORY [ -p PRODUCT ] [ -e EDITION ] [ --versions CONSTRAINT ]
+           [ --force ] [ --allow-missing-checksum ] [ --jobs N ]
+           [ --release-base URL ] [ --package-dir DIR ]
+
+Commands:
+  check       Render every EDITION/PRODUCT/VERSION directory into a
+              temporary tree and compare it with the committed one,
+              printing a unified diff and exiting non-zero if anything
+              differs.
+  refresh     Download the package checksums of every directory into the
+              checksum lockfile, generate/checksums.json, which is
+              otherwise the source of all checksums used during
+              generation.
+  library     Write the official-images library file of each product (or
+              only PRODUCT), listing the tags, architectures, git commit
+              and directory of each version, to stdout or into DIR.
+  list        Print the resolved details (template, base image, arches,
+              URLs, package filenames) of every directory, or only those
+              selected by -p, -e and --versions, as a table or JSON.
+  new         Add a release of PRODUCT in every edition (or only EDITION):
+              check that it is not excluded and that its packages and
+              checksums are published for every arch, generate
+              EDITION/PRODUCT/VERSION, add the checksums to the lockfile
+              and print the tags of the new images. Nothing is changed
+              unless every edition generates.
+  discover    Print each version in the release index of every product (or
+              only PRODUCT) which has no directory yet, but whose packages
+              are published for every arch. Versions older than the oldest
+              directory are only considered if selected by --versions.
+              With --scaffold, add each as new does.
+  promote     Promote the EDITION/PRODUCT/VERSION-staging directory of
+              every edition of PRODUCT (or only EDITION) to GA: check that
+              the production packages are published with the checksums the
+              staged Dockerfile verifies, then replace the directory with
+              a regenerated EDITION/PRODUCT/VERSION and print the tags of
+              the promoted images. Nothing is changed unless every edition
+              succeeds.
+  -v VERSION  Without a command, generate a single Dockerfile and its
+              resources in DIR, which must exist, with template parameters
+              overridden by --values and -t. If DIR is the version's own
+              directory, the overrides are recorded in its settings file.
+  (default)   Without a command or -v, generate each
+              EDITION/PRODUCT/VERSION directory selected by -p, -e and
+              --versions which has no Dockerfile yet (with --force, every
+              selected directory), then write docker-bake.hcl with a
+              target to build each directory.
+
+Directories are processed in parallel; failures are summarized at the
+end rather than stopping the run. Every form honors the settings file,
+.generate.json, of each EDITION/PRODUCT/VERSION directory, which may
+choose its template and arches and override template parameters.
 
 Arguments:
   BASE_DIRECTORY                  Root of "docker" repository
@@ -141,147 +134,518 @@ Options:
   -v VERSION, --version VERSION   Product version
   -e EDITION, --edition EDITION   Product edition (community/enterprise)
   -o OUTPUT_DIRECTORY             Directory to write Dockerfile to
-  -t TEMPLATE_ARG                 KEY=VALUE to provide to the template
+  -t TEMPLATE_ARG                 KEY=VALUE to provide to the template. VALUE
+                                  may be true/false, an integer, a JSON
+                                  list or a JSON string; anything else is
+                                  a string
+  --values FILE                   Object of KEY: VALUE to provide to the
+                                  template, overridden by -t; YAML if FILE
+                                  ends in .yaml or .yml, otherwise JSON
+  --versions CONSTRAINT           Only versions satisfying CONSTRAINT, eg.
+                                  ">= 7.2, < 8.0"
+  --json                          Print JSON rather than a table
+  --force                         Regenerate directories which already
+                                  contain a Dockerfile; with -v, generate
+                                  a frozen or excluded version
+  --scaffold                      Add each discovered version, rather than
+                                  only printing it
+  --allow-missing-checksum        Generate Dockerfiles which skip checksum
+                                  verification when a package checksum is
+                                  unavailable, rather than failing
+  --release-base URL              Use URL in place of the release hosts in
+                                  products.json, both for downloading and
+                                  in the generated Dockerfiles (eg. an
+                                  internal mirror, or a file:// URL)
+  --package-dir DIR               Read package checksums from DIR, laid out
+                                  like the release server, instead of
+                                  downloading them
+  -j N, --jobs N                  Number of directories to process at once
+                                  (default: number of CPUs)
   -h, --help                      Print this usage message
 `
 
 	args, _ := docopt.ParseDoc(usage)
 	baseDir = args["BASE_DIRECTORY"].(string)
+	allowMissingChecksum = args["--allow-missing-checksum"].(bool)
+	if args["--release-base"] != nil {
+		releaseBase = strings.TrimSuffix(args["--release-base"].(string), "/")
+	}
+	if args["--package-dir"] != nil {
+		fetcher = DirFetcher{Dir: args["--package-dir"].(string)}
+	}
+	if args["--jobs"] != nil {
+		n, err := strconv.Atoi(args["--jobs"].(string))
+		if err != nil || n < 1 {
+			log.Fatalf("--jobs must be a positive number, not %q", args["--jobs"])
+		}
+		jobs = n
+	}
 
-	if args["--product"] != nil {
+	var err error
+	registry, err = loadRegistry(path.Join(baseDir, "generate", "products.json"))
+	if err != nil {
+		log.Fatalf("Error loading product registry: %v", err)
+	}
+
+	versionCustomizations, err = loadVersionCustomizations(
+		path.Join(baseDir, "generate", "version_customizations.json"),
+	)
+	if err != nil {
+		log.Fatalf("Error loading version customizations: %v", err)
+	}
+
+	checksumsFile = path.Join(baseDir, "generate", "checksums.json")
+	checksums, err = loadChecksums(checksumsFile)
+	if err != nil {
+		log.Fatalf("Error loading checksums: %v", err)
+	}
+
+	if args["check"].(bool) {
+		log.Println("Checking generated files")
+		drifted, err := checkAllDockerfiles(os.Stdout)
+		if err != nil {
+			log.Fatalf("Check failed: %v", err)
+		}
+		if drifted > 0 {
+			log.Fatalf("%d directories or files differ from their templates", drifted)
+		}
+	} else if args["refresh"].(bool) {
+		log.Println("Refreshing checksums")
+		failures, err := refreshChecksums()
+		if err != nil {
+			log.Fatalf("Refresh failed: %v", err)
+		}
+		if failures > 0 {
+			log.Fatalf("%d checksums could not be downloaded", failures)
+		}
+	} else if args["library"].(bool) {
+		products := []Product{}
+		if args["--product"] != nil {
+			products = append(products, Product(args["--product"].(string)))
+		}
+		outputDir := ""
+		if args["-o"] != nil {
+			outputDir = args["-o"].(string)
+		}
+		if err := writeLibraryFiles(products, outputDir); err != nil {
+			log.Fatalf("Error writing library files: %v", err)
+		}
+	} else if args["list"].(bool) {
+		filter, err := versionDirFilter(args)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if err := listVariants(os.Stdout, filter, args["--json"].(bool)); err != nil {
+			log.Fatalf("List failed: %v", err)
+		}
+		return
+	} else if args["new"].(bool) {
+		edition := Edition("")
+		if args["--edition"] != nil {
+			edition = Edition(args["--edition"].(string))
+		}
+		tags, err := newRelease(
+			Product(args["--product"].(string)),
+			args["--version"].(string),
+			edition,
+		)
+		if err != nil {
+			log.Fatalf("Failed: %v", err)
+		}
+		for _, tag := range tags {
+			fmt.Println(tag)
+		}
+	} else if args["promote"].(bool) {
+		edition := Edition("")
+		if args["--edition"] != nil {
+			edition = Edition(args["--edition"].(string))
+		}
+		tags, err := promoteRelease(
+			Product(args["--product"].(string)),
+			args["--version"].(string),
+			edition,
+		)
+		if err != nil {
+			log.Fatalf("Failed: %v", err)
+		}
+		for _, tag := range tags {
+			fmt.Println(tag)
+		}
+	} else if args["discover"].(bool) {
+		filter, err := versionDirFilter(args)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		dirs, err := discoverReleases(filter)
+		if err != nil {
+			log.Fatalf("Discovery failed: %v", err)
+		}
+		if !args["--scaffold"].(bool) {
+			for _, dir := range dirs {
+				fmt.Println(dir)
+			}
+			return
+		}
+		tags, err := scaffoldReleases(dirs)
+		if err != nil {
+			log.Fatalf("Failed: %v", err)
+		}
+		for _, tag := range tags {
+			fmt.Println(tag)
+		}
+	} else if args["--version"] != nil {
 		log.Println("Generating single product")
-		generateOneDockerfile(
+		valuesFile := ""
+		if args["--values"] != nil {
+			valuesFile = args["--values"].(string)
+		}
+		overrides, err := generateOverrides(args["-t"].([]string), valuesFile)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		err = generateOneDockerfile(
 			Edition(args["--edition"].(string)),
 			Product(args["--product"].(string)),
 			args["--version"].(string),
 			args["-o"].(string),
-			generateOverrides(args["-t"].([]string)),
+			overrides,
 			false,
+			args["--force"].(bool),
 		)
+		if err != nil {
+			log.Fatalf("Failed: %v", err)
+		}
 	} else {
 		log.Println("Generating multiple products")
-		generateAllDockerfiles()
+		filter, err := versionDirFilter(args)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		failed, err := generateAllDockerfiles(filter, args["--force"].(bool))
+		if err != nil {
+			log.Fatalf("Failed: %v", err)
+		}
+		if failed > 0 {
+			log.Fatalf("%d directories failed to generate", failed)
+		}
+		if err := writeBakeFile(); err != nil {
+			log.Fatalf("Error writing %s: %v", bakeFilename, err)
+		}
 	}
 
 	log.Printf("Successfully finished!")
 }
 
-func generateOverrides(args []string) (retval map[string]any) {
+// VersionDir identifies one EDITION/PRODUCT/VERSION directory
+type VersionDir struct {
+	Edition Edition
+	Product Product
+	Version string
+}
+
+// allVersionDirs finds every EDITION/PRODUCT/VERSION directory under
+// baseDir for the registered editions and products
+func allVersionDirs() []VersionDir {
+	dirs := []VersionDir{}
+	for _, edition := range registry.Editions {
+		for _, product := range registry.productNames() {
+			// find corresponding directory for this edition/product combo
+			dir := path.Join(baseDir, string(edition), string(product))
 
-	retval = map[string]any{}
-	for _, mapping := range args {
-		vals := strings.Split(mapping, "=")
-		if len(vals) != 2 {
-			log.Fatalf("-t '%s' not of form KEY=VALUE", mapping)
+			// find all version subdirectories (must match regex)
+			for _, ver := range versionSubdirectories(dir) {
+				dirs = append(dirs, VersionDir{edition, product, ver})
+			}
 		}
-		retval[vals[0]] = vals[1]
 	}
+	return dirs
+}
 
-	return
+func (dir VersionDir) String() string {
+	return path.Join(string(dir.Edition), string(dir.Product), dir.Version)
 }
 
-func generateAllDockerfiles() {
-	for _, edition := range default_editions {
-		for _, product := range default_products {
-			// find corresponding directory for this edition/product combo
-			dir := path.Join(baseDir, string(edition), string(product))
+// sortVersionDirs returns a copy of dirs sorted by product (in registry
+// order), edition and version
+func sortVersionDirs(dirs []VersionDir) ([]VersionDir, error) {
+	versions := map[VersionDir]ProductVersion{}
+	for _, dir := range dirs {
+		v, err := ParseVersion(dir.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", dir, err)
+		}
+		versions[dir] = v
+	}
+	order := map[Product]int{}
+	for i, product := range registry.productNames() {
+		order[product] = i
+	}
 
-			// find all version subdirectories (must match regex)
-			versions := versionSubdirectories(dir)
+	sorted := append([]VersionDir{}, dirs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Product != b.Product {
+			return order[a.Product] < order[b.Product]
+		}
+		if a.Edition != b.Edition {
+			return a.Edition < b.Edition
+		}
+		return versions[a].Compare(versions[b]) < 0
+	})
+	return sorted, nil
+}
 
-			// for each version
-			for _, ver := range versions {
+// generatedVersionDirs returns every version directory which is neither
+// frozen nor excluded by its product's exclusions, logging the reason for
+// each one skipped. A directory whose exclusions cannot be evaluated
+// because its version is malformed is kept, so that generating it
+// reports the problem. Any other failure to tell whether a directory is
+// excluded, such as an unreadable marker file, is an error.
+func generatedVersionDirs() ([]VersionDir, error) {
+	dirs := []VersionDir{}
+	for _, dir := range allVersionDirs() {
+		reason, excluded, err := dir.excluded()
+		var badVersion *BadVersionError
+		if err != nil && !errors.As(err, &badVersion) {
+			return nil, fmt.Errorf("%v: %w", dir, err)
+		}
+		if excluded {
+			log.Printf("Skipping %v: %s", dir, reason)
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
 
-				if skipGeneration.Matches(product, ver) {
-					log.Printf("Skipping generation for %v %v %v", product, edition, ver)
-					continue
-				}
-				generateOneDockerfile(edition, product, ver, "", nil, true)
-			}
+// VersionDirFilter selects version directories by product, edition and
+// version. Empty fields match every directory.
+type VersionDirFilter struct {
+	Product  Product
+	Edition  Edition
+	Versions VersionConstraint
+}
+
+// versionDirFilter returns the filter given by the -p, -e and --versions
+// options
+func versionDirFilter(args docopt.Opts) (VersionDirFilter, error) {
+	filter := VersionDirFilter{}
+	if args["--product"] != nil {
+		filter.Product = Product(args["--product"].(string))
+		if _, ok := registry.product(filter.Product); !ok {
+			return filter, &UnknownProductError{Product: filter.Product}
 		}
 	}
+	if args["--edition"] != nil {
+		filter.Edition = Edition(args["--edition"].(string))
+	}
+	if args["--versions"] != nil {
+		constraint, err := ParseConstraint(args["--versions"].(string))
+		if err != nil {
+			return filter, err
+		}
+		filter.Versions = constraint
+	}
+	return filter, nil
+}
+
+// Matches returns true if the filter selects dir
+func (filter VersionDirFilter) Matches(dir VersionDir) bool {
+	if filter.Product != "" && dir.Product != filter.Product {
+		return false
+	}
+	if filter.Edition != "" && dir.Edition != filter.Edition {
+		return false
+	}
+	if filter.Versions != nil {
+		v, err := ParseVersion(dir.Version)
+		if err != nil || !filter.Versions.Check(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the directories among dirs which the filter selects
+func (filter VersionDirFilter) apply(dirs []VersionDir) []VersionDir {
+	selected := []VersionDir{}
+	for _, dir := range dirs {
+		if filter.Matches(dir) {
+			selected = append(selected, dir)
+		}
+	}
+	return selected
+}
+
+// generateAllDockerfiles generates every version directory selected by
+// filter which does not yet have a Dockerfile (or, if force is set, every
+// selected directory), in parallel, returning the number of directories
+// which failed. It is an error if the directories cannot be listed.
+func generateAllDockerfiles(filter VersionDirFilter, force bool) (int, error) {
+	dirs, err := generatedVersionDirs()
+	if err != nil {
+		return 0, err
+	}
+	dirs = filter.apply(dirs)
+	errs := runParallel(dirs, func(i int, dir VersionDir) error {
+		variant, err := newVariant(dir.Edition, dir.Product, dir.Version)
+		if err != nil {
+			return err
+		}
+		_, err = generateVariant(variant, !force)
+		return err
+	})
+	return reportFailures(dirs, errs), nil
 }
 
+// generateOneDockerfile generates a single version of a product into
+// outputDir. A frozen or excluded version is refused unless force is set.
 func generateOneDockerfile(
 	edition Edition, product Product, ver string, outputDir string,
-	overrides map[string]any, noOverwrite bool,
+	overrides map[string]any, noOverwrite bool, force bool,
 ) error {
-	// Start with a basic DockerfileVariant, then tweak if necessary
+	dir := VersionDir{edition, product, ver}
+	reason, excluded, err := dir.excluded()
+	if err != nil {
+		return fmt.Errorf("%v: %w", dir, err)
+	}
+	if excluded && !force {
+		return fmt.Errorf("%w; use --force to generate it anyway", &ExcludedError{Dir: dir, Reason: reason})
+	}
+
+	variant, err := newVariant(edition, product, ver)
+	if err != nil {
+		return fmt.Errorf("%v/%v/%v: %w", edition, product, ver, err)
+	}
+	variant.OutputDir = outputDir
+
+	// Overrides given on the command line take precedence over those
+	// in the version directory's settings
+	merged := map[string]any{}
+	for key, value := range variant.TemplateOverrides {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	variant.TemplateOverrides = merged
+
+	// Now generate the Dockerfile(s) based on the constructed variant
+	generated, err := generateVariant(variant, noOverwrite)
+	if err != nil {
+		return fmt.Errorf("%v/%v/%v: %w", edition, product, ver, err)
+	}
+	// The overrides only describe the Dockerfile if it was generated with
+	// them, rather than left as it was
+	if !generated {
+		return nil
+	}
+	if err := recordOverrides(variant, overrides); err != nil {
+		return fmt.Errorf("%v/%v/%v: %w", edition, product, ver, err)
+	}
+
+	return nil
+}
+
+// newVariant resolves the DockerfileVariant for a version directory of a
+// product, according to the rules in the product registry
+func newVariant(edition Edition, product Product, ver string) (DockerfileVariant, error) {
+	spec, ok := registry.product(product)
+	if !ok {
+		return DockerfileVariant{}, &UnknownProductError{Product: product}
+	}
+
+	productVersion, err := ParseVersion(ver)
+	if err != nil {
+		return DockerfileVariant{}, err
+	}
+
 	variant := DockerfileVariant{
-		Edition:           edition,
-		Product:           product,
-		Version:           strings.TrimSuffix(ver, "-staging"),
-		TargetVersion:     strings.TrimSuffix(ver, "-staging"),
-		Arches:            []Arch{Archamd64},
-		IsStaging:         strings.HasSuffix(ver, "-staging"),
-		TemplateFilename:  "Dockerfile.template",
-		OutputDir:         outputDir,
-		TemplateOverrides: overrides,
-	}
-
-	productVer, _ := intVer(variant.Version)
-
-	// Update according to special cases based on Product and Version.
-	if product == ProductServer {
-		if productVer == 70003 {
-			// CBD-4603: 7.0.3 actually builds from 7.0.3-MP1 for complete
-			// Log4Shell remediation
-			variant.Version = "7.0.3-MP1"
-		}
-
-		if productVer >= 70100 {
-			// 7.1.0 and higher also support arm64
-			variant.Arches = append(variant.Arches, Archarm64)
-		}
-	} else if product == ProductSyncGw {
-		if productVer <= 30003 {
-			variant.TemplateFilename = "Dockerfile.centos.template"
-		} else {
-			variant.TemplateFilename = "Dockerfile.ubuntu.template"
-			variant.Arches = append(variant.Arches, Archarm64)
+		Edition:       edition,
+		Product:       product,
+		Version:       strings.TrimSuffix(ver, "-staging"),
+		TargetVersion: strings.TrimSuffix(ver, "-staging"),
+		IsStaging:     productVersion.Staging,
+	}
+
+	if alias, ok := spec.VersionAliases[variant.Version]; ok {
+		variant.Version = alias
+	}
+
+	// The directory's own settings, if any, take precedence over the
+	// registry
+	settings, err := loadVersionSettings(settingsFile(edition, product, ver))
+	if err != nil {
+		return variant, err
+	}
+	for _, arch := range settings.Arches {
+		if !containsArch(spec.arches(), arch) {
+			return variant, fmt.Errorf("%s: %v has no %q arch", settingsFile(edition, product, ver), product, arch)
+		}
+	}
+	variant.TemplateOverrides = settings.Overrides
+
+	if settings.Template != "" {
+		variant.TemplateFilename = settings.Template
+	} else {
+		templateFilename, ok, err := spec.Templates.first(variant.Version)
+		if err != nil {
+			return variant, err
 		}
-	} else if product == ProductSandbox {
-		if productVer >= 71000 {
-			// 7.1.0 and higher also support arm64
-			variant.Arches = append(variant.Arches, Archarm64)
+		if !ok {
+			return variant, &MissingTemplateError{Product: product, Version: variant.Version}
 		}
-	} else if product == ProductColumnar || product == ProductEnterpriseAnalytics {
-		variant.Arches = append(variant.Arches, Archarm64)
+		variant.TemplateFilename = templateFilename
 	}
 
-	// Now generate the Dockerfile(s) based on the constructed variant
-	if err := generateVariant(variant, noOverwrite); err != nil {
-		log.Fatalf("Failed (%v/%v/%v): %v", edition, product, ver, err)
+	if len(settings.Arches) > 0 {
+		variant.Arches = settings.Arches
+		return variant, nil
+	}
+	arches, err := spec.Arches.all(variant.Version)
+	if err != nil {
+		return variant, err
+	}
+	if len(arches) == 0 {
+		return variant, fmt.Errorf("no arches for version %v", variant.Version)
+	}
+	for _, arch := range arches {
+		variant.Arches = append(variant.Arches, Arch(arch))
 	}
 
-	return nil
+	return variant, nil
 }
 
-func generateVariant(variant DockerfileVariant, noOverwrite bool) error {
+// generateVariant writes the Dockerfile, scripts and README of variant.
+// With noOverwrite, an existing Dockerfile (and its resources) is left as
+// it is. It returns whether the Dockerfile was written.
+func generateVariant(variant DockerfileVariant, noOverwrite bool) (bool, error) {
+	generated := false
 	_, err := os.Stat(variant.dockerfile())
 	if noOverwrite && !os.IsNotExist(err) {
 		log.Printf("%s exists, not regenerating...", variant.dockerfile())
 	} else {
 		if err := generateDockerfile(variant); err != nil {
-			return err
+			return false, err
 		}
+		generated = true
 
 		if err := deployScriptResources(variant); err != nil {
-			return err
+			return generated, err
 		}
 
 		if err := deployConfigResources(variant); err != nil {
-			return err
+			return generated, err
 		}
 	}
 
 	// We always want to ensure the readme is updated, to avoid the current
 	// description on docker hub being overwritten by legacy documentation.
 	if err := deployReadme(variant); err != nil {
-		return err
+		return generated, err
 	}
 
-	return nil
+	return generated, nil
 }
 
 func generateDockerfile(variant DockerfileVariant) error {
@@ -305,66 +669,9 @@ func generateDockerfile(variant DockerfileVariant) error {
 
 	log.Printf("template: %v", sourceTemplate)
 	log.Printf("product: %v", variant.Product)
-	var params map[string]any
-
-	if variant.Product == ProductServer {
-		// template parameters
-		params = map[string]any{
-			"CB_VERSION":         variant.VersionWithSubstitutions(),
-			"CB_PACKAGE":         variant.serverPackageFile(Archgeneric),
-			"CB_PACKAGE_NAME":    variant.serverPackageName(),
-			"CB_EXTRA_DEPS":      variant.extraDependencies(),
-			"CB_SHA256_arm64":    variant.getSHA256(Archarm64),
-			"CB_SHA256_amd64":    variant.getSHA256(Archamd64),
-			"CB_RELEASE_URL":     variant.releaseURL(),
-			"DOCKER_BASE_IMAGE":  variant.dockerBaseImage(),
-			"PKG_COMMAND":        variant.serverPkgCommand(),
-			"SYSTEMD_WORKAROUND": variant.systemdWorkaround(),
-			"CB_MULTIARCH":       len(variant.Arches) > 1,
-			"CB_SKIP_CHECKSUM":   "false",
-		}
-
-	} else if variant.Product == ProductSyncGw {
-		// template parameters
-		params = map[string]any{
-			"SYNC_GATEWAY_PACKAGE_URL":      variant.sgPackageUrl(),
-			"SYNC_GATEWAY_PACKAGE_FILENAME": variant.sgPackageFilename(),
-			"DOCKER_BASE_IMAGE":             variant.dockerBaseImage(),
-		}
-
-	} else if variant.Product == ProductSandbox {
-		// template parameters
-		params = map[string]any{
-			"CB_VERSION":        variant.VersionWithSubstitutions(),
-			"DOCKER_BASE_IMAGE": variant.dockerBaseImage(),
-			"CB_MULTIARCH":      len(variant.Arches) > 1,
-		}
-
-	} else if variant.Product == ProductColumnar {
-		// template parameters
-		params = map[string]any{
-			"CB_VERSION":        variant.VersionWithSubstitutions(),
-			"CB_PACKAGE":        variant.columnarPackageFile(Archgeneric),
-			"CB_RELEASE_URL":    variant.releaseURL(),
-			"DOCKER_BASE_IMAGE": variant.dockerBaseImage(),
-			"CB_MULTIARCH":      len(variant.Arches) > 1,
-		}
-	} else if variant.Product == ProductEnterpriseAnalytics {
-		// template parameters
-		params = map[string]any{
-			"CB_VERSION":        variant.VersionWithSubstitutions(),
-			"CB_PACKAGE":        variant.enterpriseAnalyticsPackageFile(Archgeneric),
-			"CB_RELEASE_URL":    variant.releaseURL(),
-			"DOCKER_BASE_IMAGE": variant.dockerBaseImage(),
-			"CB_MULTIARCH":      len(variant.Arches) > 1,
-		}
-	} else if variant.Product == ProductEdgeServer {
-		// template parameters
-		params = map[string]any{
-			"CB_RELEASE_URL":    variant.releaseURL(),
-			"CB_PACKAGE_NAME":   variant.edgeServerPackageFile(Archgeneric),
-			"DOCKER_BASE_IMAGE": variant.dockerBaseImage(),
-		}
+	params, err := variant.params()
+	if err != nil {
+		return err
 	}
 
 	// Apply any user-requested template overrides
@@ -372,28 +679,34 @@ func generateDockerfile(variant DockerfileVariant) error {
 		params[key] = value
 	}
 
-	// open a file at destPath
-	out, err := os.Create(targetDockerfile)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-
 	templateBytes, err := ioutil.ReadFile(sourceTemplate)
 	if err != nil {
-		return err
+		return &MissingTemplateError{
+			Product: variant.Product,
+			Version: variant.Version,
+			Path:    sourceTemplate,
+			Err:     err,
+		}
 	}
 
-	tmpl, err := template.New("docker").Parse(string(templateBytes))
+	// Referring to a parameter which is not set is an error, rather than
+	// rendering as "<no value>"
+	tmpl, err := template.New("docker").Option("missingkey=error").Funcs(variant.funcs(Archgeneric)).Parse(string(templateBytes))
 	if err != nil {
 		return err
 	}
-	err = tmpl.Execute(out, params)
-	if err != nil {
+	if err := addPartials(tmpl); err != nil {
 		return err
 	}
+	if err := checkOverridesUsed(tmpl, variant.TemplateOverrides); err != nil {
+		return fmt.Errorf("%s: %w", sourceTemplate, err)
+	}
 
-	return nil
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, params); err != nil {
+		return err
+	}
+	return os.WriteFile(targetDockerfile, out.Bytes(), 0644)
 }
 
 func deployResourcesSubdir(variant DockerfileVariant, subdir string) error {
@@ -477,18 +790,15 @@ func CopyFile(source string, dest string) (err error) {
 	defer destfile.Close()
 
 	_, err = io.Copy(destfile, sourcefile)
-	if err == nil {
-		sourceinfo, err := os.Stat(source)
-		if err == nil {
-			err = os.Chmod(dest, sourceinfo.Mode())
-			if err != nil {
-				log.Printf("Error chmod %v", dest)
-			}
-		}
-
+	if err != nil {
+		return err
 	}
 
-	return
+	sourceinfo, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(dest, sourceinfo.Mode())
 }
 
 func CopyDir(source string, dest string) (err error) {
@@ -505,9 +815,16 @@ func CopyDir(source string, dest string) (err error) {
 		return err
 	}
 
-	directory, _ := os.Open(source)
+	directory, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer directory.Close()
 
 	objects, err := directory.Readdir(-1)
+	if err != nil {
+		return err
+	}
 
 	for _, obj := range objects {
 
@@ -518,19 +835,16 @@ func CopyDir(source string, dest string) (err error) {
 		if obj.IsDir() {
 			// create sub-directories - recursively
 			err = CopyDir(sourcefilepointer, destinationfilepointer)
-			if err != nil {
-				fmt.Println(err)
-			}
 		} else {
 			// perform copy
 			err = CopyFile(sourcefilepointer, destinationfilepointer)
-			if err != nil {
-				fmt.Println(err)
-			}
+		}
+		if err != nil {
+			return err
 		}
 
 	}
-	return
+	return nil
 }
 
 type DockerfileVariant struct {
@@ -551,224 +865,119 @@ type DockerfileVariant struct {
 	TemplateOverrides map[string]any
 }
 
-func (variant DockerfileVariant) getSHA256(arch Arch) string {
-	var sha256url string
-	if variant.Product == "couchbase-server" {
-		sha256url = variant.releaseURL() + "/" +
-			variant.serverPackageFile(arch) + ".sha256"
+// spec returns the registry entry for this variant's product
+func (variant DockerfileVariant) spec() (*ProductSpec, error) {
+	spec, ok := registry.product(variant.Product)
+	if !ok {
+		return nil, &UnknownProductError{Product: variant.Product}
 	}
+	return spec, nil
+}
 
-	resp, err := http.Get(sha256url)
-	log.Print(sha256url)
+// versionCheck returns true if this variant's version satisfies the
+// given constraint, eg. ">= 7.1.0"
+func (variant DockerfileVariant) versionCheck(constraint string) (bool, error) {
+	rules := Rules{{Versions: constraint}}
+	if err := rules.compile(); err != nil {
+		return false, err
+	}
+	return rules[0].matches(variant.Version)
+}
 
-	if err != nil || resp.StatusCode != 200 {
-		log.Printf("Error downloading SHA256 file")
-		return "MISSING_SHA256_ERROR"
-	} else {
-		defer resp.Body.Close()
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			log.Printf("Error download content of SHA256 file")
-			return "HTTP_ERROR"
-		}
-		return strings.Fields(fmt.Sprintf("%s", body))[0]
+func (variant DockerfileVariant) dockerBaseImage() (string, error) {
+	spec, err := variant.spec()
+	if err != nil {
+		return "", err
 	}
+	image, ok, err := spec.BaseImage.baseImagePolicy(variant.Product, "baseImage", variant.Version)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("no base image for %v %v", variant.Product, variant.Version)
+	}
+	return variant.render(image, Archgeneric)
 }
 
-func (variant DockerfileVariant) dockerBaseImage() string {
-	switch variant.Product {
-	case ProductSyncGw:
-		productVer, _ := intVer(variant.Version)
-		if strings.Contains(variant.Version, "forestdb") {
-			return "tleyden5iwx/forestdb"
-		}
-		if productVer <= 30003 {
-			return "centos:centos7"
-		} else {
-			return fmt.Sprintf("ubuntu:%s", variant.ubuntuVersion())
-		}
-	case ProductEdgeServer:
-		return fmt.Sprintf("ubuntu:%s", variant.ubuntuVersion())
-	case ProductServer:
-		return fmt.Sprintf("ubuntu:%s", variant.ubuntuVersion())
-	case ProductSandbox:
-		return fmt.Sprintf("couchbase/server:%s", variant.Version)
-	case ProductColumnar:
-		return fmt.Sprintf("ubuntu:%s", variant.ubuntuVersion())
-	case ProductEnterpriseAnalytics:
-		return fmt.Sprintf("ubuntu:%s", variant.ubuntuVersion())
-	default:
-		log.Printf("Failed %v", variant.Product)
-		panic("Unexpected product")
-	}
-}
-
-func (variant DockerfileVariant) serverPkgCommand() string {
-	// Currently all Server Dockerfiles are based on Ubuntu, so this is
-	// always "apt-get". However we did some work in the Dockerfile
-	// template to support "yum" as well. Leaving that in place for now
-	// in case we work on integrating the RHCC Dockerfile in future.
-	return "apt-get"
-}
-
-func (variant DockerfileVariant) systemdWorkaround() bool {
-	if variant.Product == ProductServer {
-		ver, _ := intVer(variant.Version)
-		if ver < 70000 {
-			return true
+// ubuntuVersion returns the Ubuntu release this variant is based on. For
+// products built from another product's image (eg. server-sandbox), that
+// is the Ubuntu release of the same version of the other product.
+func (variant DockerfileVariant) ubuntuVersion() (string, error) {
+	spec, err := variant.spec()
+	if err != nil {
+		return "", err
+	}
+	if spec.BasedOn != "" {
+		base, ok := registry.product(spec.BasedOn)
+		if !ok {
+			return "", &UnknownProductError{Product: spec.BasedOn}
 		}
+		spec = base
 	}
-	return false
+	ubuntu, _, err := spec.Ubuntu.baseImagePolicy(spec.Name, "ubuntu", variant.Version)
+	return ubuntu, err
 }
 
-func intVer(v string) (int64, error) {
-	baseVer := strings.Split(v, "-")[0]
-	sections := strings.Split(baseVer, ".")
-	intVerSection := func(n int) string {
-		return fmt.Sprintf("%02s", sections[n])
+// VersionWithSubstitutions returns the version handed to the template,
+// which the product's versionSubstitutions may replace
+func (variant DockerfileVariant) VersionWithSubstitutions() (string, error) {
+	spec, err := variant.spec()
+	if err != nil {
+		return "", err
 	}
-	s := ""
-	for i := 0; i < 3; i++ {
-		s += intVerSection(i)
+	substitution, ok, err := spec.VersionSubstitutions.first(variant.Version)
+	if err != nil || !ok {
+		return variant.Version, err
 	}
-	return strconv.ParseInt(s, 10, 64)
+	return variant.render(substitution, Archgeneric)
 }
 
-func (variant DockerfileVariant) isMadHatterOrNewer() bool {
-	ver, _ := intVer(variant.Version)
-	return ver >= 60500
-}
+// Generate the package filename for this variant, using the product's
+// packageFile rules unless the version has been customized. arch is
+// translated according to the product's packageArches.
+// eg: couchbase-server-enterprise_7.1.1-linux_amd64.deb
+func (variant DockerfileVariant) packageFile(arch Arch) (string, error) {
+	spec, err := variant.spec()
+	if err != nil {
+		return "", err
+	}
+
+	if versionCustomization, ok := variant.versionCustomization(); ok &&
+		versionCustomization.PackageFilename != "" {
+		return versionCustomization.packageFile(spec.packageArch(arch)), nil
+	}
 
-func (variant DockerfileVariant) ubuntuVersion() string {
-	v1, err := version.NewVersion(variant.Version)
+	packageFile, ok, err := spec.PackageFile.first(variant.Version)
 	if err != nil {
-		log.Fatalf("go-version failed to parse %v", variant.Version)
+		return "", err
 	}
-	switch variant.Product {
-	case ProductSyncGw:
-		return "22.04"
-	case ProductEdgeServer:
-		return "22.04"
-	case ProductColumnar:
-		return "22.04"
-	case ProductEnterpriseAnalytics:
-		return "24.04"
-	case ProductServer:
-		version4, err := version.NewConstraint(">= 4.0, < 5.0")
-		if err != nil {
-			log.Fatalf("Error creating version constraint 4.x: %v", err)
-		}
-		version5To6Dot0Dot0, err := version.NewConstraint(">= 5.0, <= 6.0.0")
-		if err != nil {
-			log.Fatalf("Error creating version constraint 5.x--6.0.0: %v", err)
-		}
-		version6Dot0Dot1To6Dot6Dot1, err := version.NewConstraint(">= 6.0.1, <= 6.6.1")
-		if err != nil {
-			log.Fatalf("Error creating version constraint 6.0.1--6.6.1: %v", err)
-		}
-		version6Dot6Dot2To7Dot1Dot6, err := version.NewConstraint(">= 6.6.2, <= 7.1.6")
-		if err != nil {
-			log.Fatalf("Error creating version constraint 6.6.2--7.1.6: %v", err)
-		}
-		version7Dot2Dot0To7Dot2Dot5, err := version.NewConstraint(">= 7.2.0, <= 7.2.5")
-		if err != nil {
-			log.Fatalf("Error creating version constraint 7.2.0--7.2.5: %v", err)
-		}
-		version7Dot6Dot0To7Dot6Dot1, err := version.NewConstraint(">= 7.6.0, <= 7.6.1")
-		if err != nil {
-			log.Fatalf("Error creating version constraint 7.6.0--7.6.1: %v", err)
-		}
-		if version4.Check(v1) {
-			return "14.04"
-		} else if version5To6Dot0Dot0.Check(v1) {
-			return "16.04"
-		} else if version6Dot0Dot1To6Dot6Dot1.Check(v1) {
-			return "18.04"
-		} else if version6Dot6Dot2To7Dot1Dot6.Check(v1) {
-			return "20.04"
-		} else if version7Dot2Dot0To7Dot2Dot5.Check(v1) {
-			return "22.04"
-		} else if version7Dot6Dot0To7Dot6Dot1.Check(v1) {
-			return "22.04"
-		}
-		return "24.04"
-	}
-	return ""
-}
-
-// Get the version for this variant, possibly doing substitutions
-func (variant DockerfileVariant) VersionWithSubstitutions() string {
-	if variant.Product == "sync-gateway" {
-		// if version is 0.0.0-xxx, replace with feature/xxx.
-		// (example: 0.0.0-forestdb -> feature/forestdb)
-		extraStuff := extraStuffAfterVersion(variant.Version)
-		switch extraStuff {
-		case "forestdb":
-			return fmt.Sprintf("feature/%v", extraStuff)
-		default:
-			return variant.Version
-		}
-	}
-	return variant.Version
-}
-
-// Given a version like "1.0.0" or "0.0.0-forestdb", return
-// the extra stuff after the version, like "" or "forestdb" (respectively)
-func extraStuffAfterVersion(version string) string {
-	re := regexp.MustCompile(`[0-9]*.[0-9]*.[0-9]*-?(.*)`)
-	result := re.FindStringSubmatch(version)
-	if len(result) > 1 {
-		group1 := result[1]
-		return group1
-	}
-	return ""
-}
-
-// Generate the package filename for this variant:
-// eg: couchbase-server-enterprise-7.1.1-linux_amd64.deb
-func (variant DockerfileVariant) serverPackageFile(arch Arch) string {
-	serverVer, _ := intVer(variant.Version)
-	if serverVer >= 70100 {
-		// From Neo onwards, use "linux" package since it's all the same.
-		return fmt.Sprintf(
-			"%v-%v_%v-linux_%v.deb",
-			variant.Product,
-			variant.Edition,
-			variant.Version,
-			arch,
-		)
-	} else {
-		// For earlier releases, no arm64 builds, so just hardcode amd64
-		return fmt.Sprintf(
-			"%v-%v_%v-ubuntu%v_amd64.deb",
-			variant.Product,
-			variant.Edition,
-			variant.Version,
-			variant.ubuntuVersion(),
-		)
+	if !ok {
+		return "", fmt.Errorf("no package file for %v %v", variant.Product, variant.Version)
 	}
+	return variant.render(packageFile, spec.packageArch(arch))
 }
 
-// Generate the package name (couchbase-server or couchbase-server-community)
-// for this variant
-func (variant DockerfileVariant) serverPackageName() string {
-	if variant.Edition == EditionCommunity {
-		return "couchbase-server-community"
-	} else {
-		return "couchbase-server"
+// Find the full package download URL for this variant
+func (variant DockerfileVariant) packageURL(arch Arch) (string, error) {
+	spec, err := variant.spec()
+	if err != nil {
+		return "", err
 	}
-}
 
-// Specify any extra dependencies, based on variant
-func (variant DockerfileVariant) extraDependencies() string {
-	if variant.Product == "couchbase-server" {
-		if variant.isMadHatterOrNewer() {
-			return "bzip2"
-		} else {
-			return "python-httplib2"
-		}
+	if versionCustomization, ok := variant.versionCustomization(); ok &&
+		versionCustomization.PackageUrl != "" {
+		return versionCustomization.packageURL(spec.packageArch(arch)), nil
 	}
-	return ""
+
+	releaseURL, err := variant.releaseURL()
+	if err != nil {
+		return "", err
+	}
+	packageFile, err := variant.packageFile(arch)
+	if err != nil {
+		return "", err
+	}
+	return releaseURL + "/" + packageFile, nil
 }
 
 func (variant DockerfileVariant) targetDir() string {
@@ -777,134 +986,72 @@ func (variant DockerfileVariant) targetDir() string {
 		return variant.OutputDir
 	}
 
-	// Here we use TargetVersion rather than Version
-	version := string(variant.TargetVersion)
-	if variant.IsStaging {
-		version = fmt.Sprintf("%s-staging", version)
-	}
 	targetDir := path.Join(
 		baseDir,
 		string(variant.Edition),
 		string(variant.Product),
-		version,
+		variant.imageVersion(),
 	)
 	return targetDir
 }
 
-func (variant DockerfileVariant) dockerfile() string {
-	return path.Join(variant.targetDir(), "Dockerfile")
-}
-
-func (variant DockerfileVariant) releaseURL() string {
-	if variant.Product == ProductServer {
-		if variant.IsStaging {
-			return "http://packages-staging.couchbase.com/releases/" + variant.Version
-		} else {
-			return "https://packages.couchbase.com/releases/" + variant.Version
-		}
-	} else {
-		if variant.IsStaging {
-			return "http://packages-staging.couchbase.com/releases/" + string(variant.Product) + "/" + variant.Version
-		} else {
-			return "https://packages.couchbase.com/releases/" + string(variant.Product) + "/" + variant.Version
-		}
-	}
-}
-
-// Find the package URL for this Sync Gateway version
-func (variant DockerfileVariant) sgPackageUrl() string {
-	var packagesBaseUrl string
+// imageVersion is the version of the Docker image, which is also its
+// directory name: TargetVersion rather than Version, with a -staging
+// suffix for staging builds
+func (variant DockerfileVariant) imageVersion() string {
 	if variant.IsStaging {
-		packagesBaseUrl = "http://packages-staging.couchbase.com/releases/couchbase-sync-gateway"
-	} else {
-		packagesBaseUrl = "http://packages.couchbase.com/releases/couchbase-sync-gateway"
+		return fmt.Sprintf("%s-staging", variant.TargetVersion)
 	}
+	return variant.TargetVersion
+}
 
-	versionCustomization, hasCustomization := variant.versionCustomization()
-
-	switch hasCustomization {
-	case true:
-		return fmt.Sprintf("%s", versionCustomization.PackageUrl)
-	default:
-		sgFileName := variant.sgPackageFilename()
-
-		return fmt.Sprintf(
-			"%s/%s/%s",
-			packagesBaseUrl,
-			variant.Version,
-			sgFileName,
-		)
-	}
+// hasArch returns true if this variant is built for the given arch
+func (variant DockerfileVariant) hasArch(arch Arch) bool {
+	return containsArch(variant.Arches, arch)
 }
 
-func (variant DockerfileVariant) sgPackageFilename() string {
-	versionCustomization, hasCustomization := variant.versionCustomization()
-	switch hasCustomization {
-	case true:
-		return fmt.Sprintf("%s", versionCustomization.PackageFilename)
-	default:
-		productVer, _ := intVer(variant.Version)
-		// Containers for SGW versions <= 3.0.3 were only produced for x64
-		if productVer <= 30003 {
-			return fmt.Sprintf(
-				"couchbase-sync-gateway-%s_%s_@@ARCH@@.rpm",
-				strings.ToLower(string(variant.Edition)),
-				variant.Version,
-			)
-		} else {
-			return fmt.Sprintf(
-				"couchbase-sync-gateway-%s_%s_@@ARCH@@.deb",
-				strings.ToLower(string(variant.Edition)),
-				variant.Version,
-			)
+// containsArch returns true if arch is one of arches
+func containsArch(arches []Arch, arch Arch) bool {
+	for _, a := range arches {
+		if a == arch {
+			return true
 		}
 	}
+	return false
 }
 
-func (variant DockerfileVariant) versionCustomization() (v VersionCustomization, exists bool) {
-	// eg, "sync-gateway_community_2.0.0-build
-	key := variant.versionCustomizationKey()
-
-	v, exists = versionCustomizations[key]
-	return v, exists
-}
-
-func (variant DockerfileVariant) versionCustomizationKey() string {
-	return fmt.Sprintf("%s_%s_%s", variant.Product, variant.Edition, variant.Version)
-}
-
-// Generate the package filename for couchbase-edge-server:
-// eg: couchbase-edge-server_1.0.0_amd64.deb
-func (variant DockerfileVariant) edgeServerPackageFile(arch Arch) string {
-	return fmt.Sprintf(
-		"%v_%v_%v.deb",
-		variant.Product,
-		variant.Version,
-		arch,
-	)
+func (variant DockerfileVariant) dockerfile() string {
+	return path.Join(variant.targetDir(), "Dockerfile")
 }
 
-// Generate the package filename for this variant:
-// eg: couchbase-columnar-enterprise-1.1.0-linux_amd64.deb
-func (variant DockerfileVariant) columnarPackageFile(arch Arch) string {
-	return fmt.Sprintf(
-		"%v-%v_%v-linux_%v.deb",
-		variant.Product,
-		variant.Edition,
-		variant.Version,
-		arch,
-	)
+// releaseHost returns the staging or production package host for this
+// variant
+func (variant DockerfileVariant) releaseHost() (string, error) {
+	if releaseBase != "" {
+		return releaseBase, nil
+	}
+	spec, err := variant.spec()
+	if err != nil {
+		return "", err
+	}
+	hosts := spec.releaseHosts()
+	if variant.IsStaging {
+		return hosts.Staging, nil
+	}
+	return hosts.Production, nil
 }
 
-// Generate the package filename for this variant:
-// eg: enterprise-analytics_2.0.0-linux_arm64.deb
-func (variant DockerfileVariant) enterpriseAnalyticsPackageFile(arch Arch) string {
-	return fmt.Sprintf(
-		"%v_%v-linux_%v.deb",
-		variant.Product,
-		variant.Version,
-		arch,
-	)
+func (variant DockerfileVariant) releaseURL() (string, error) {
+	if versionCustomization, ok := variant.versionCustomization(); ok {
+		if releaseURL := versionCustomization.releaseURL(); releaseURL != "" {
+			return releaseURL, nil
+		}
+	}
+	spec, err := variant.spec()
+	if err != nil {
+		return "", err
+	}
+	return variant.render(spec.ReleaseURL, Archgeneric)
 }
 
 // exists returns whether the given file or directory exists or not
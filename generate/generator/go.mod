@@ -4,4 +4,4 @@ go 1.18
 
 require github.com/docopt/docopt-go v0.0.0-20180111231733-ee0de3bc6815
 
-require github.com/hashicorp/go-version v1.7.0 // indirect
+require gopkg.in/yaml.v3 v3.0.1
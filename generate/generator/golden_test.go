@@ -0,0 +1,241 @@
+package main
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenVersionDirs are rendered by TestGolden. They are chosen to cover
+// every rule in the product registry: version aliases, the first arm64
+// releases, the CentOS and Ubuntu Sync Gateway templates, version
+// customizations, staging builds and each Ubuntu LTS range.
+var goldenVersionDirs = []VersionDir{
+	{EditionEnterprise, "couchbase-server", "4.6.5"},
+	{EditionEnterprise, "couchbase-server", "5.5.0"},
+	{EditionEnterprise, "couchbase-server", "6.6.0"},
+	{EditionEnterprise, "couchbase-server", "6.6.2"},
+	{EditionEnterprise, "couchbase-server", "7.0.3"},
+	{EditionEnterprise, "couchbase-server", "7.1.0"},
+	{EditionEnterprise, "couchbase-server", "7.2.4"},
+	{EditionEnterprise, "couchbase-server", "7.6.1"},
+	{EditionEnterprise, "couchbase-server", "7.6.2"},
+	{EditionEnterprise, "couchbase-server", "8.0.0-staging"},
+	{EditionCommunity, "couchbase-server", "7.6.2"},
+	{EditionCommunity, "sync-gateway", "1.5.0"},
+	{EditionCommunity, "sync-gateway", "1.1.0-forestdb_bucket"},
+	{EditionCommunity, "sync-gateway", "2.0.0-devbuild"},
+	{EditionEnterprise, "sync-gateway", "3.0.3"},
+	{EditionEnterprise, "sync-gateway", "3.0.4"},
+	{EditionEnterprise, "sync-gateway", "4.0.0"},
+	{EditionEnterprise, "server-sandbox", "7.0.5"},
+	{EditionEnterprise, "server-sandbox", "7.1.0"},
+	{EditionEnterprise, "couchbase-columnar", "1.0.0"},
+	{EditionEnterprise, "couchbase-edge-server", "1.0.0"},
+	{EditionEnterprise, "enterprise-analytics", "2.0.0"},
+}
+
+// packageServer is a fake release server. It answers requests for .sha256
+// files with a checksum derived from the package URL, so golden files do
+// not depend on the real package server or the checksum lockfile, and
+// reports every package as published.
+type packageServer struct{}
+
+func (packageServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasSuffix(r.URL.Path, ".sha256") {
+		return
+	}
+	packageURL := strings.TrimSuffix(originalURL(r), ".sha256")
+	sum := sha256.Sum256([]byte(packageURL))
+	fmt.Fprintf(w, "%x  %s\n", sum, path.Base(packageURL))
+}
+
+// routeTransport sends every request to server, whatever its URL, so that
+// one test server stands in for the production and staging release hosts
+// as well as the hosts of version customizations. The handler sees the
+// original host and scheme, which originalURL puts back together.
+type routeTransport struct {
+	server *url.URL
+}
+
+func (rt routeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	routed := req.Clone(req.Context())
+	routed.Host = req.URL.Host
+	routed.Header.Set("X-Forwarded-Proto", req.URL.Scheme)
+	routed.URL.Scheme, routed.URL.Host = rt.server.Scheme, rt.server.Host
+	return http.DefaultTransport.RoundTrip(routed)
+}
+
+// originalURL returns the URL a request was made for before routeTransport
+// routed it
+func originalURL(r *http.Request) string {
+	return r.Header.Get("X-Forwarded-Proto") + "://" + r.Host + r.URL.Path
+}
+
+// routedFetcher returns the default fetcher, with HTTP requests routed to
+// server
+func routedFetcher(server *httptest.Server) Fetcher {
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	httpFetcher := HTTPFetcher{Client: &http.Client{Transport: routeTransport{serverURL}}}
+	return SchemeFetcher{"http": httpFetcher, "https": httpFetcher, "file": FileFetcher{}}
+}
+
+// repoDir is the root of the repository. Tests run against a copy of
+// testdata/tree instead, which holds the version directories they need and
+// links to the real generate directory, so that they are unaffected by the
+// version directories and settings files of the repository itself.
+var repoDir = filepath.Join("..", "..")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	tree, err := os.MkdirTemp("", "generator-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(tree)
+	if err := CopyDir(filepath.Join("testdata", "tree"), tree); err != nil {
+		log.Fatal(err)
+	}
+	generateDir, err := filepath.Abs(filepath.Join(repoDir, "generate"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.Symlink(generateDir, filepath.Join(tree, "generate")); err != nil {
+		log.Fatal(err)
+	}
+	baseDir = tree
+
+	registry, err = loadRegistry(filepath.Join(baseDir, "generate", "products.json"))
+	if err != nil {
+		log.Fatalf("Error loading product registry: %v", err)
+	}
+	versionCustomizations, err = loadVersionCustomizations(
+		filepath.Join(baseDir, "generate", "version_customizations.json"),
+	)
+	if err != nil {
+		log.Fatalf("Error loading version customizations: %v", err)
+	}
+
+	server := httptest.NewServer(packageServer{})
+	defer server.Close()
+	checksums = Checksums{}
+	fetcher = routedFetcher(server)
+
+	// generateDockerfile() is chatty
+	log.SetOutput(io.Discard)
+
+	return m.Run()
+}
+
+func TestGolden(t *testing.T) {
+	for _, dir := range goldenVersionDirs {
+		dir := dir
+		t.Run(dir.String(), func(t *testing.T) {
+			variant, err := newVariant(dir.Edition, dir.Product, dir.Version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			variant.OutputDir = t.TempDir()
+			if err := generateDockerfile(variant); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := os.ReadFile(variant.dockerfile())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goldenFile := filepath.Join("testdata", "golden", dir.String(), "Dockerfile")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(goldenFile), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(goldenFile, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if diff := unifiedDiff(goldenFile, "generated", string(want), string(got)); diff != "" {
+				t.Errorf("Dockerfile differs from golden file (run go test -update to accept):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGoldenBakeFile(t *testing.T) {
+	got, err := renderBakeFile(goldenVersionDirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goldenFile := filepath.Join("testdata", "golden", bakeFilename)
+	if *update {
+		if err := os.WriteFile(goldenFile, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if diff := unifiedDiff(goldenFile, "generated", string(want), got); diff != "" {
+		t.Errorf("%s differs from golden file (run go test -update to accept):\n%s", bakeFilename, diff)
+	}
+}
+
+func TestGoldenLibraryFile(t *testing.T) {
+	spec, _ := registry.product("couchbase-server")
+	fakeCommit := func(dir VersionDir) (string, error) {
+		sum := sha256.Sum256([]byte(dir.String()))
+		return fmt.Sprintf("%x", sum[:20]), nil
+	}
+	got, err := renderLibraryFile(spec, goldenVersionDirs, fakeCommit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goldenFile := filepath.Join("testdata", "golden", "library", spec.libraryFilename())
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenFile, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if diff := unifiedDiff(goldenFile, "generated", string(want), got); diff != "" {
+		t.Errorf("library file differs from golden file (run go test -update to accept):\n%s", diff)
+	}
+}
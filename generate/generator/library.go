@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// LibrarySettings are the repository-wide fields of the official-images
+// library files
+type LibrarySettings struct {
+	Maintainers []string `json:"maintainers"`
+	GitRepo     string   `json:"gitRepo"`
+	// Architectures maps Docker's architecture names to those used by
+	// the library files, eg. arm64 -> arm64v8
+	Architectures map[Arch]string `json:"architectures"`
+}
+
+// maintainerPattern matches the form official-images requires of a
+// maintainer, eg. Jane Doe <jane@example.com> (@jdoe)
+var maintainerPattern = regexp.MustCompile(`^[^<>,]+ <[^<>@ ]+@[^<> ]+> \(@[A-Za-z0-9-]+\)$`)
+
+// validate checks the fields official-images rejects a library file
+// without
+func (settings LibrarySettings) validate() error {
+	if len(settings.Maintainers) == 0 {
+		return fmt.Errorf("library: no maintainers")
+	}
+	for _, maintainer := range settings.Maintainers {
+		if !maintainerPattern.MatchString(maintainer) {
+			return fmt.Errorf("library: maintainer %q is not of the form Name <email> (@github)", maintainer)
+		}
+	}
+	if settings.GitRepo == "" {
+		return fmt.Errorf("library: no gitRepo")
+	}
+	return nil
+}
+
+// gitCommitFunc returns the commit which last changed a version directory
+type gitCommitFunc func(dir VersionDir) (string, error)
+
+// lastGitCommit returns the commit which last changed dir in the git
+// repository at baseDir
+func lastGitCommit(dir VersionDir) (string, error) {
+	out, err := exec.Command("git", "-C", baseDir, "log", "-1", "--format=%H", "--", dir.String()).Output()
+	if err != nil {
+		return "", fmt.Errorf("git log %v: %v", dir, err)
+	}
+	commit := strings.TrimSpace(string(out))
+	if commit == "" {
+		return "", fmt.Errorf("%v has not been committed", dir)
+	}
+	return commit, nil
+}
+
+// libraryFilename returns the name of a product's library file, which is
+// its repository name without the namespace, eg. server for
+// couchbase/server
+func (spec *ProductSpec) libraryFilename() string {
+	return path.Base(spec.Image)
+}
+
+// renderLibraryFile returns the docker-library manifest for a product,
+// with an entry for each of its directories among dirs, newest first.
+// Staging directories are left out.
+func renderLibraryFile(spec *ProductSpec, dirs []VersionDir, gitCommit gitCommitFunc) (string, error) {
+	index, err := newTagIndex(dirs)
+	if err != nil {
+		return "", err
+	}
+	sorted, err := sortVersionDirs(dirs)
+	if err != nil {
+		return "", err
+	}
+
+	if err := registry.Library.validate(); err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "# %s\n# Generated by generate/generator; do not edit.\n\n", spec.Image)
+	fmt.Fprintf(&out, "Maintainers: %s\n", strings.Join(registry.Library.Maintainers, ",\n             "))
+	fmt.Fprintf(&out, "GitRepo: %s\n", registry.Library.GitRepo)
+
+	for i := len(sorted) - 1; i >= 0; i-- {
+		dir := sorted[i]
+		if dir.Product != spec.Name {
+			continue
+		}
+		variant, err := newVariant(dir.Edition, dir.Product, dir.Version)
+		if err != nil {
+			return "", fmt.Errorf("%v: %w", dir, err)
+		}
+		if variant.IsStaging {
+			continue
+		}
+
+		tags, err := variant.tagNames(index)
+		if err != nil {
+			return "", fmt.Errorf("%v: %w", dir, err)
+		}
+		if len(tags) == 0 {
+			continue
+		}
+		arches := []string{}
+		for _, arch := range variant.Arches {
+			arches = append(arches, registry.Library.Architectures[arch])
+		}
+		commit, err := gitCommit(dir)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(&out, "\nTags: %s\n", strings.Join(tags, ", "))
+		fmt.Fprintf(&out, "Architectures: %s\n", strings.Join(arches, ", "))
+		fmt.Fprintf(&out, "GitCommit: %s\n", commit)
+		fmt.Fprintf(&out, "Directory: %s\n", dir)
+	}
+
+	return out.String(), nil
+}
+
+// writeLibraryFiles writes the library file for each of products (or for
+// every product, if products is empty) into outputDir, or to stdout if
+// outputDir is empty
+func writeLibraryFiles(products []Product, outputDir string) error {
+	if len(products) == 0 {
+		products = registry.productNames()
+	}
+
+	dirs := allVersionDirs()
+	for _, product := range products {
+		spec, ok := registry.product(product)
+		if !ok {
+			return &UnknownProductError{Product: product}
+		}
+		contents, err := renderLibraryFile(spec, dirs, lastGitCommit)
+		if err != nil {
+			return err
+		}
+
+		if outputDir == "" {
+			fmt.Print(contents)
+			continue
+		}
+		filename := path.Join(outputDir, spec.libraryFilename())
+		if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+			return err
+		}
+		fmt.Println(filename)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLibrarySettingsValidate(t *testing.T) {
+	tests := []struct {
+		maintainers []string
+		err         string
+	}{
+		{[]string{"Couchbase Docker Team <docker@couchbase.com> (@cb-robot)"}, ""},
+		{[]string{"Jane Doe <jane@example.com> (@jdoe)", "John Doe <john@example.com> (@john-doe)"}, ""},
+		{nil, "no maintainers"},
+		{[]string{"docker@couchbase.com"}, "not of the form"},
+		{[]string{"Couchbase Docker Team <docker@couchbase.com>"}, "not of the form"},
+	}
+	for _, test := range tests {
+		settings := LibrarySettings{Maintainers: test.maintainers, GitRepo: "https://github.com/couchbase/docker.git"}
+		err := settings.validate()
+		if test.err == "" && err != nil {
+			t.Errorf("%q: %v", test.maintainers, err)
+		} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("%q: got error %v, want %q", test.maintainers, err, test.err)
+		}
+	}
+}
+
+func TestRenderLibraryFileNoMaintainers(t *testing.T) {
+	oldLibrary := registry.Library
+	t.Cleanup(func() { registry.Library = oldLibrary })
+	registry.Library.Maintainers = nil
+
+	spec, _ := registry.product("couchbase-server")
+	noCommit := func(dir VersionDir) (string, error) { return "", nil }
+	if _, err := renderLibraryFile(spec, nil, noCommit); err == nil {
+		t.Errorf("library file with no maintainers was rendered")
+	}
+}
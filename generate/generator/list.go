@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// VariantInfo is the resolved description of a version directory, as
+// printed by the list command
+type VariantInfo struct {
+	Directory     string  `json:"directory"`
+	Edition       Edition `json:"edition"`
+	Product       Product `json:"product"`
+	Version       string  `json:"version"`
+	TargetVersion string  `json:"targetVersion"`
+	Template      string  `json:"template"`
+	BaseImage     string  `json:"baseImage"`
+	Arches        []Arch  `json:"arches"`
+	Staging       bool    `json:"staging"`
+	ReleaseURL    string  `json:"releaseUrl"`
+	// PackageFiles maps each arch to its package filename
+	PackageFiles map[Arch]string `json:"packageFiles"`
+}
+
+// variantInfo resolves the VariantInfo of a version directory, without
+// generating anything
+func variantInfo(dir VersionDir) (VariantInfo, error) {
+	variant, err := newVariant(dir.Edition, dir.Product, dir.Version)
+	if err != nil {
+		return VariantInfo{}, err
+	}
+
+	info := VariantInfo{
+		Directory:     dir.String(),
+		Edition:       variant.Edition,
+		Product:       variant.Product,
+		Version:       variant.Version,
+		TargetVersion: variant.TargetVersion,
+		Template:      variant.TemplateFilename,
+		Arches:        variant.Arches,
+		Staging:       variant.IsStaging,
+		PackageFiles:  map[Arch]string{},
+	}
+	if info.BaseImage, err = variant.dockerBaseImage(); err != nil {
+		return info, err
+	}
+	if info.ReleaseURL, err = variant.releaseURL(); err != nil {
+		return info, err
+	}
+	spec, err := variant.spec()
+	if err != nil {
+		return info, err
+	}
+	if len(spec.PackageFile) == 0 {
+		// eg. server-sandbox, which installs nothing itself
+		return info, nil
+	}
+	for _, arch := range variant.Arches {
+		if info.PackageFiles[arch], err = variant.packageFile(arch); err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
+
+// listVariants writes the VariantInfo of every version directory matching
+// filter to out, as a table or as JSON
+func listVariants(out io.Writer, filter VersionDirFilter, asJSON bool) error {
+	dirs, err := sortVersionDirs(filter.apply(allVersionDirs()))
+	if err != nil {
+		return err
+	}
+
+	infos := []VariantInfo{}
+	for _, dir := range dirs {
+		info, err := variantInfo(dir)
+		if err != nil {
+			return fmt.Errorf("%v: %w", dir, err)
+		}
+		infos = append(infos, info)
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(infos)
+	}
+
+	table := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "DIRECTORY\tVERSION\tTARGET\tTEMPLATE\tBASE IMAGE\tARCHES\tSTAGING\tRELEASE URL\tPACKAGE FILES")
+	for _, info := range infos {
+		arches := []string{}
+		packageFiles := []string{}
+		for _, arch := range info.Arches {
+			arches = append(arches, string(arch))
+			// Products which install no package of their own have none
+			if packageFile, ok := info.PackageFiles[arch]; ok {
+				packageFiles = append(packageFiles, packageFile)
+			}
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Directory,
+			info.Version,
+			info.TargetVersion,
+			info.Template,
+			info.BaseImage,
+			strings.Join(arches, ","),
+			strconv.FormatBool(info.Staging),
+			emptyAsDash(info.ReleaseURL),
+			emptyAsDash(strings.Join(packageFiles, ",")),
+		)
+	}
+	return table.Flush()
+}
+
+func emptyAsDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
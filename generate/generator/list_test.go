@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVersionDirFilter(t *testing.T) {
+	constraint, err := ParseConstraint(">= 7.2, < 8.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := VersionDirFilter{Product: "couchbase-server", Edition: EditionEnterprise, Versions: constraint}
+
+	tests := []struct {
+		dir  VersionDir
+		want bool
+	}{
+		{VersionDir{EditionEnterprise, "couchbase-server", "7.6.2"}, true},
+		{VersionDir{EditionEnterprise, "couchbase-server", "7.2.0-staging"}, true},
+		{VersionDir{EditionEnterprise, "couchbase-server", "8.0.0"}, false},
+		{VersionDir{EditionEnterprise, "couchbase-server", "7.1.6"}, false},
+		{VersionDir{EditionCommunity, "couchbase-server", "7.6.2"}, false},
+		{VersionDir{EditionEnterprise, "server-sandbox", "7.6.2"}, false},
+	}
+	for _, test := range tests {
+		if got := filter.Matches(test.dir); got != test.want {
+			t.Errorf("Matches(%v) = %v, want %v", test.dir, got, test.want)
+		}
+	}
+
+	if !(VersionDirFilter{}).Matches(VersionDir{EditionCommunity, "sync-gateway", "1.1.0-forestdb_bucket"}) {
+		t.Errorf("empty filter does not match everything")
+	}
+}
+
+func TestListVariantsJSON(t *testing.T) {
+	constraint, err := ParseConstraint("7.0.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := VersionDirFilter{Product: "couchbase-server", Edition: EditionEnterprise, Versions: constraint}
+
+	var out strings.Builder
+	if err := listVariants(&out, filter, true); err != nil {
+		t.Fatal(err)
+	}
+
+	var infos []VariantInfo
+	if err := json.Unmarshal([]byte(out.String()), &infos); err != nil {
+		t.Fatal(err)
+	}
+	want := []VariantInfo{{
+		Directory:     "enterprise/couchbase-server/7.0.3",
+		Edition:       EditionEnterprise,
+		Product:       "couchbase-server",
+		Version:       "7.0.3-MP1",
+		TargetVersion: "7.0.3",
+		Template:      "Dockerfile.template",
+		BaseImage:     "ubuntu:20.04",
+		Arches:        []Arch{Archamd64},
+		ReleaseURL:    "https://packages.couchbase.com/releases/7.0.3-MP1",
+		PackageFiles: map[Arch]string{
+			Archamd64: "couchbase-server-enterprise_7.0.3-MP1-ubuntu20.04_amd64.deb",
+		},
+	}}
+	if !reflect.DeepEqual(infos, want) {
+		t.Errorf("list = %+v, want %+v", infos, want)
+	}
+}
+
+func TestListVariantsTable(t *testing.T) {
+	tests := []struct {
+		product Product
+		dir     string
+		// packageFiles is the last column of dir's row
+		packageFiles string
+	}{
+		{"couchbase-server", "enterprise/couchbase-server/7.6.2",
+			"couchbase-server-enterprise_7.6.2-linux_amd64.deb,couchbase-server-enterprise_7.6.2-linux_arm64.deb"},
+		// server-sandbox installs no package of its own
+		{"server-sandbox", "enterprise/server-sandbox/7.1.0", "-"},
+	}
+	for _, test := range tests {
+		var out strings.Builder
+		if err := listVariants(&out, VersionDirFilter{Product: test.product}, false); err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, line := range strings.Split(out.String(), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 || fields[0] != test.dir {
+				continue
+			}
+			found = true
+			if got := fields[len(fields)-1]; got != test.packageFiles {
+				t.Errorf("%s: package files = %q, want %q", test.dir, got, test.packageFiles)
+			}
+		}
+		if !found {
+			t.Errorf("%s is not listed in\n%s", test.dir, out.String())
+		}
+	}
+}
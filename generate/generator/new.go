@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// newRelease adds version ver of product for every edition it is released
+// in (or only edition, if given): it checks that the version is not
+// excluded and that every package and its checksum is published,
+// generates the version directories in a work directory, moves them into
+// place, records the checksums in the lockfile and rewrites
+// docker-bake.hcl. Nothing is changed unless every edition checks out and
+// generates, and if moving any directory into place fails, every
+// directory is taken out again. It returns the tags of the new images.
+func newRelease(product Product, ver string, edition Edition) ([]string, error) {
+	editions, err := selectEditions(product, edition)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := []DockerfileVariant{}
+	newChecksums := Checksums{}
+	for _, edition := range editions {
+		variant, err := newVariant(edition, product, ver)
+		if err != nil {
+			return nil, fmt.Errorf("%v/%v/%v: %w", edition, product, ver, err)
+		}
+		dir := VersionDir{edition, product, variant.imageVersion()}
+		reason, excluded, err := dir.excluded()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", dir, err)
+		}
+		if excluded {
+			return nil, &ExcludedError{Dir: dir, Reason: reason}
+		}
+		dirExists, err := exists(variant.targetDir())
+		if err != nil {
+			return nil, err
+		}
+		if dirExists {
+			return nil, fmt.Errorf("%s already exists", variant.targetDir())
+		}
+		if err := variant.verifyPackages(newChecksums); err != nil {
+			return nil, fmt.Errorf("%v/%v/%v: %w", edition, product, ver, err)
+		}
+		variants = append(variants, variant)
+	}
+
+	workDir, err := os.MkdirTemp(baseDir, ".new-"+ver+"-")
+	if err != nil {
+		return nil, err
+	}
+	// Generation reads the new checksums from the lockfile, which is only
+	// written once every directory is in place
+	for key, sum := range newChecksums {
+		checksums[key] = sum
+	}
+	discard := func() {
+		for key := range newChecksums {
+			delete(checksums, key)
+		}
+		os.RemoveAll(workDir)
+	}
+
+	for _, variant := range variants {
+		variant.OutputDir = filepath.Join(workDir, string(variant.Edition))
+		err := os.Mkdir(variant.OutputDir, 0755)
+		if err == nil {
+			_, err = generateVariant(variant, false)
+		}
+		if err != nil {
+			discard()
+			return nil, fmt.Errorf("%v/%v/%v: %w", variant.Edition, product, ver, err)
+		}
+	}
+
+	moves := renames{}
+	for _, variant := range variants {
+		log.Printf("Creating %s", variant.targetDir())
+		err := os.MkdirAll(filepath.Dir(variant.targetDir()), 0755)
+		if err == nil {
+			err = moves.rename(filepath.Join(workDir, string(variant.Edition)), variant.targetDir())
+		}
+		if err != nil {
+			moves.undo()
+			discard()
+			return nil, err
+		}
+	}
+
+	if len(newChecksums) > 0 {
+		if err := checksums.save(checksumsFile); err != nil {
+			moves.undo()
+			discard()
+			return nil, err
+		}
+	}
+	if err := os.RemoveAll(workDir); err != nil {
+		return nil, err
+	}
+
+	if err := writeBakeFile(); err != nil {
+		return nil, err
+	}
+	return releaseTags(variants)
+}
+
+// selectEditions returns the editions product is released in, or only
+// edition if it is given
+func selectEditions(product Product, edition Edition) ([]Edition, error) {
+	spec, ok := registry.product(product)
+	if !ok {
+		return nil, &UnknownProductError{Product: product}
+	}
+
+	editions := spec.editions()
+	if edition == "" {
+		return editions, nil
+	}
+	for _, e := range editions {
+		if e == edition {
+			return []Edition{edition}, nil
+		}
+	}
+	return nil, fmt.Errorf("%v is not released in the %v edition", product, edition)
+}
+
+// releaseTags returns the tags of the images of variants, given the
+// version directories now in the tree
+func releaseTags(variants []DockerfileVariant) ([]string, error) {
+	index, err := newTagIndex(allVersionDirs())
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	for _, variant := range variants {
+		variantTags, err := variant.tags(index)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, variantTags...)
+	}
+	return tags, nil
+}
+
+// verifyPackages checks that this variant's package for every arch is
+// published, along with its checksum if the product verifies checksums.
+// The checksums are added to sums.
+func (variant DockerfileVariant) verifyPackages(sums Checksums) error {
+	spec, err := variant.spec()
+	if err != nil {
+		return err
+	}
+	if len(spec.PackageFile) == 0 {
+		// eg. server-sandbox, which installs nothing itself
+		return nil
+	}
+
+	for _, arch := range variant.Arches {
+		packageURL, err := variant.packageURL(arch)
+		if err != nil {
+			return err
+		}
+		log.Printf("Checking %s", packageURL)
+		if err := fetcher.Head(packageURL); err != nil {
+			return &FetchError{URL: packageURL, Err: err}
+		}
+
+		if !spec.Checksums {
+			continue
+		}
+		sum, err := variant.getSHA256(arch)
+		if err != nil {
+			return err
+		}
+		if sum != "" {
+			sums[variant.checksumKey(arch)] = sum
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// withScratchTree points the generator at an empty tree which shares the
+// real generate/ directory, and at a package directory, restoring the
+// globals afterwards
+func withScratchTree(t *testing.T) (tree string, packages string) {
+	t.Helper()
+
+	oldBaseDir, oldFetcher, oldChecksums, oldChecksumsFile, oldFetches := baseDir, fetcher, checksums, checksumsFile, sha256Fetches
+	t.Cleanup(func() {
+		baseDir, fetcher, checksums, checksumsFile, sha256Fetches = oldBaseDir, oldFetcher, oldChecksums, oldChecksumsFile, oldFetches
+	})
+
+	tree = t.TempDir()
+	generateDir, err := filepath.Abs(filepath.Join(oldBaseDir, "generate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(generateDir, filepath.Join(tree, "generate")); err != nil {
+		t.Fatal(err)
+	}
+
+	packages = t.TempDir()
+	baseDir = tree
+	fetcher = DirFetcher{Dir: packages}
+	checksums = Checksums{}
+	checksumsFile = filepath.Join(t.TempDir(), "checksums.json")
+	sha256Fetches = map[string]*sha256Fetch{}
+	return tree, packages
+}
+
+// publish creates a fake package, and its .sha256 file, in packages
+func publish(t *testing.T, packages string, relPath string) string {
+	t.Helper()
+	filename := filepath.Join(packages, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(relPath), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := fmt.Sprintf("%064x", len(relPath))
+	contents := fmt.Sprintf("%s  %s\n", sum, filepath.Base(filename))
+	if err := os.WriteFile(filename+".sha256", []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return sum
+}
+
+func TestNewRelease(t *testing.T) {
+	tree, packages := withScratchTree(t)
+	sum := publish(t, packages, "releases/couchbase-edge-server/1.9.0/couchbase-edge-server_1.9.0_amd64.deb")
+	if err := os.MkdirAll(filepath.Join(tree, "enterprise", "couchbase-edge-server", "1.8.0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := newRelease("couchbase-edge-server", "1.9.0", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"couchbase/edge-server:1.9.0", "couchbase/edge-server:1.9", "couchbase/edge-server:latest"}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %q, want %q", tags, want)
+	}
+	for _, name := range []string{"Dockerfile", "README.md"} {
+		if _, err := os.Stat(filepath.Join(tree, "enterprise", "couchbase-edge-server", "1.9.0", name)); err != nil {
+			t.Error(err)
+		}
+	}
+	if got := checksums["couchbase-edge-server/enterprise/1.9.0/amd64"]; got != sum {
+		t.Errorf("lockfile checksum = %q, want %q", got, sum)
+	}
+	bake, err := os.ReadFile(filepath.Join(tree, bakeFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bake), `"couchbase-edge-server-enterprise-1_9_0"`) {
+		t.Errorf("%s has no target for the new release", bakeFilename)
+	}
+}
+
+func TestNewReleaseMissingPackage(t *testing.T) {
+	tree, packages := withScratchTree(t)
+	// Only the enterprise amd64 package is published
+	publish(t, packages, "releases/9.0.0/couchbase-server-enterprise_9.0.0-linux_amd64.deb")
+
+	if _, err := newRelease("couchbase-server", "9.0.0", ""); err == nil {
+		t.Fatal("newRelease succeeded with packages missing")
+	}
+
+	for _, edition := range []string{"community", "enterprise"} {
+		if _, err := os.Stat(filepath.Join(tree, edition, "couchbase-server", "9.0.0")); !os.IsNotExist(err) {
+			t.Errorf("%s directory was created: %v", edition, err)
+		}
+	}
+	if len(checksums) != 0 {
+		t.Errorf("lockfile was updated: %v", checksums)
+	}
+}
+
+func TestNewReleaseExcluded(t *testing.T) {
+	tree, _ := withScratchTree(t)
+
+	_, err := newRelease("sync-gateway", "2.0.0", "")
+	var excludedErr *ExcludedError
+	if !errors.As(err, &excludedErr) {
+		t.Fatalf("got error %v, want an ExcludedError", err)
+	}
+	if _, err := os.Stat(filepath.Join(tree, "community", "sync-gateway", "2.0.0")); !os.IsNotExist(err) {
+		t.Errorf("excluded directory was created: %v", err)
+	}
+}
+
+func TestNewReleaseRollback(t *testing.T) {
+	tree, packages := withScratchTree(t)
+	publish(t, packages, "releases/couchbase-edge-server/1.9.0/couchbase-edge-server_1.9.0_amd64.deb")
+	// The lockfile cannot be saved, once the new directory is in place
+	checksumsFile = filepath.Join(t.TempDir(), "missing", "checksums.json")
+
+	if _, err := newRelease("couchbase-edge-server", "1.9.0", ""); err == nil {
+		t.Fatal("newRelease succeeded without saving the lockfile")
+	}
+
+	if _, err := os.Stat(filepath.Join(tree, "enterprise", "couchbase-edge-server", "1.9.0")); !os.IsNotExist(err) {
+		t.Errorf("new directory was left behind: %v", err)
+	}
+	work, err := filepath.Glob(filepath.Join(tree, ".new-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(work) > 0 {
+		t.Errorf("work directories were left behind: %q", work)
+	}
+	if len(checksums) != 0 {
+		t.Errorf("checksums were kept: %v", checksums)
+	}
+}
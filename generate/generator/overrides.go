@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// generateOverrides returns the template parameter overrides given by
+// the object in valuesFile (if any) and then by each -t KEY=VALUE
+// argument, which take precedence
+func generateOverrides(args []string, valuesFile string) (map[string]any, error) {
+	overrides := map[string]any{}
+	if valuesFile != "" {
+		values, err := loadValues(valuesFile)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			overrides[key] = value
+		}
+	}
+
+	for _, mapping := range args {
+		key, value, ok := strings.Cut(mapping, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("-t '%s' not of form KEY=VALUE", mapping)
+		}
+		typed, err := parseOverrideValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("-t '%s': %v", mapping, err)
+		}
+		overrides[key] = typed
+	}
+	return overrides, nil
+}
+
+// parseOverrideValue converts the VALUE of a -t KEY=VALUE argument to
+// the type it looks like, so that eg. false is false inside {{ if }}:
+//
+//	true, false        bool
+//	42, -1             int
+//	["a", 1]           list, as JSON
+//	"true", "42"       string, as JSON, for a string which would
+//	                   otherwise be converted
+//	anything else      string
+func parseOverrideValue(value string) (any, error) {
+	switch {
+	case value == "true" || value == "false":
+		return value == "true", nil
+	case strings.HasPrefix(value, "["):
+		var list []any
+		if err := unmarshalValue([]byte(value), &list); err != nil {
+			return nil, fmt.Errorf("bad JSON list: %v", err)
+		}
+		return list, nil
+	case strings.HasPrefix(value, `"`):
+		var s string
+		if err := json.Unmarshal([]byte(value), &s); err != nil {
+			return nil, fmt.Errorf("bad JSON string: %v", err)
+		}
+		return s, nil
+	}
+	// Only canonical integers, so that eg. 007 stays as written
+	if n, err := strconv.Atoi(value); err == nil && strconv.Itoa(n) == value {
+		return n, nil
+	}
+	return value, nil
+}
+
+// loadValues reads a --values file, an object mapping template
+// parameters to their values, in YAML if filename ends in .yaml or .yml
+// and in JSON otherwise
+func loadValues(filename string) (map[string]any, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]any{}
+	switch filepath.Ext(filename) {
+	case ".yaml", ".yml":
+		// yaml.v3 already decodes whole numbers as ints
+		err = yaml.Unmarshal(data, &values)
+	default:
+		err = unmarshalValue(data, &values)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return values, nil
+}
+
+// unmarshalValue decodes JSON into v, turning whole numbers into ints
+// rather than float64s, so that they render as written
+func unmarshalValue(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case *[]any:
+		for i := range *v {
+			(*v)[i] = convertNumbers((*v)[i])
+		}
+	case *map[string]any:
+		for key := range *v {
+			(*v)[key] = convertNumbers((*v)[key])
+		}
+	}
+	return nil
+}
+
+// convertNumbers replaces the json.Numbers in a decoded JSON value with
+// ints, where they are whole, or float64s
+func convertNumbers(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		if n, err := strconv.Atoi(value.String()); err == nil {
+			return n
+		}
+		f, _ := value.Float64()
+		return f
+	case []any:
+		for i := range value {
+			value[i] = convertNumbers(value[i])
+		}
+	case map[string]any:
+		for key := range value {
+			value[key] = convertNumbers(value[key])
+		}
+	}
+	return value
+}
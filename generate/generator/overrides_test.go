@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateOverrides(t *testing.T) {
+	valuesFile := filepath.Join(t.TempDir(), "values.json")
+	values := `{"PROFILE": "columnar", "CB_MULTIARCH": false, "PORTS": [8091, 18091], "RATIO": 0.5}`
+	if err := os.WriteFile(valuesFile, []byte(values), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := generateOverrides([]string{
+		"FROM_LOCAL_INSTALL=true",
+		"CB_MULTIARCH=true",
+		"JOBS=4",
+		"ZEROS=007",
+		"PACKAGES=[\"bzip2\", 2]",
+		"QUOTED=\"false\"",
+		"CB_RELEASE_URL=https://example.com/get?build=1&os=linux",
+		"EMPTY=",
+	}, valuesFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"PROFILE":            "columnar",
+		"CB_MULTIARCH":       true,
+		"PORTS":              []any{8091, 18091},
+		"RATIO":              0.5,
+		"FROM_LOCAL_INSTALL": true,
+		"JOBS":               4,
+		"ZEROS":              "007",
+		"PACKAGES":           []any{"bzip2", 2},
+		"QUOTED":             "false",
+		"CB_RELEASE_URL":     "https://example.com/get?build=1&os=linux",
+		"EMPTY":              "",
+	}
+	if !reflect.DeepEqual(overrides, want) {
+		t.Errorf("overrides = %#v, want %#v", overrides, want)
+	}
+}
+
+func TestGenerateOverridesErrors(t *testing.T) {
+	for _, arg := range []string{"PROFILE", "=columnar", "PACKAGES=[bzip2]", `QUOTED="false`} {
+		if _, err := generateOverrides([]string{arg}, ""); err == nil {
+			t.Errorf("-t %s was accepted", arg)
+		}
+	}
+
+	valuesFile := filepath.Join(t.TempDir(), "values.json")
+	if err := os.WriteFile(valuesFile, []byte(`["not", "an", "object"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generateOverrides(nil, valuesFile); err == nil {
+		t.Errorf("--values with a JSON list was accepted")
+	}
+
+	valuesFile = filepath.Join(t.TempDir(), "values.yaml")
+	if err := os.WriteFile(valuesFile, []byte("- not\n- an\n- object\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generateOverrides(nil, valuesFile); err == nil {
+		t.Errorf("--values with a YAML list was accepted")
+	}
+}
+
+func TestGenerateOverridesYAML(t *testing.T) {
+	values := `# Columnar profile
+PROFILE: columnar
+CB_MULTIARCH: false
+PORTS: [8091, 18091]
+RATIO: 0.5
+QUOTED: "true"
+PACKAGES:
+  - bzip2
+  - jq
+`
+	want := map[string]any{
+		"PROFILE":      "columnar",
+		"CB_MULTIARCH": true,
+		"PORTS":        []any{8091, 18091},
+		"RATIO":        0.5,
+		"QUOTED":       "true",
+		"PACKAGES":     []any{"bzip2", "jq"},
+	}
+	for _, name := range []string{"values.yaml", "values.yml"} {
+		valuesFile := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(valuesFile, []byte(values), 0644); err != nil {
+			t.Fatal(err)
+		}
+		overrides, err := generateOverrides([]string{"CB_MULTIARCH=true"}, valuesFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(overrides, want) {
+			t.Errorf("%s: overrides = %#v, want %#v", name, overrides, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"log"
+	"runtime"
+	"sync"
+)
+
+// jobs is the maximum number of version directories processed at once
+var jobs = runtime.NumCPU()
+
+// runParallel calls fn for each of dirs, using up to jobs goroutines. It
+// returns the error from each call, in the same order as dirs.
+func runParallel(dirs []VersionDir, fn func(i int, dir VersionDir) error) []error {
+	errs := make([]error, len(dirs))
+
+	workers := jobs
+	if workers < 1 {
+		workers = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = fn(i, dirs[i])
+			}
+		}()
+	}
+
+	for i := range dirs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
+
+// reportFailures logs a summary of every failed directory, returning the
+// number of failures
+func reportFailures(dirs []VersionDir, errs []error) int {
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return 0
+	}
+
+	log.Printf("%d of %d directories failed:", failed, len(dirs))
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("  %v: %v", dirs[i], err)
+		}
+	}
+	return failed
+}
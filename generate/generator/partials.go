@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// partialsDir holds the template blocks shared by every product's
+// templates, relative to baseDir. Each NAME.tmpl file defines the
+// template NAME, which a Dockerfile template includes with
+// {{ template "NAME" . }}.
+var partialsDir = path.Join("generate", "templates", "_shared")
+
+// addPartials adds the shared partials to tmpl. The final newline of each
+// file is dropped, as the line calling the template provides it.
+func addPartials(tmpl *template.Template) error {
+	filenames, err := filepath.Glob(path.Join(baseDir, partialsDir, "*.tmpl"))
+	if err != nil {
+		return err
+	}
+	for _, filename := range filenames {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.Base(filename), ".tmpl")
+		text := strings.TrimSuffix(string(data), "\n")
+		if _, err := tmpl.New(name).Parse(text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// promotion is a staging directory which is about to become GA
+type promotion struct {
+	staged DockerfileVariant
+	ga     DockerfileVariant
+	// workDir holds the GA directory while it is being generated, and the
+	// staging directory once it has been replaced
+	workDir string
+}
+
+// promoteRelease turns the VERSION-staging directory of product, in every
+// edition which has one (or only edition, if given), into the GA VERSION
+// directory: it checks that the GA packages and checksums are published
+// and that the checksums match those the staged Dockerfiles verify,
+// generates the GA directories with production URLs, swaps them in for
+// the staging ones, updates the lockfile and rewrites docker-bake.hcl.
+// Nothing is changed unless every edition checks out and generates, and
+// if swapping in any directory fails, every directory is put back. It
+// returns the tags of the promoted images.
+func promoteRelease(product Product, ver string, edition Edition) ([]string, error) {
+	v, err := ParseVersion(ver)
+	if err != nil {
+		return nil, err
+	}
+	if v.Staging {
+		return nil, fmt.Errorf("%v: give the version to promote to, without -staging", ver)
+	}
+	editions, err := selectEditions(product, edition)
+	if err != nil {
+		return nil, err
+	}
+
+	promotions := []*promotion{}
+	newChecksums := Checksums{}
+	staleChecksums := []string{}
+	for _, edition := range editions {
+		staged, err := newVariant(edition, product, ver+"-staging")
+		if err != nil {
+			return nil, fmt.Errorf("%v/%v/%v: %w", edition, product, ver, err)
+		}
+		stagedExists, err := exists(staged.targetDir())
+		if err != nil {
+			return nil, err
+		}
+		if !stagedExists {
+			continue
+		}
+
+		ga, err := newVariant(edition, product, ver)
+		if err != nil {
+			return nil, fmt.Errorf("%v/%v/%v: %w", edition, product, ver, err)
+		}
+		// The staging directory's settings move along with it
+		ga.TemplateFilename = staged.TemplateFilename
+		ga.Arches = staged.Arches
+		ga.TemplateOverrides = staged.TemplateOverrides
+		gaExists, err := exists(ga.targetDir())
+		if err != nil {
+			return nil, err
+		}
+		if gaExists {
+			return nil, fmt.Errorf("%s already exists", ga.targetDir())
+		}
+
+		if err := ga.verifyPackages(newChecksums); err != nil {
+			return nil, fmt.Errorf("%v/%v/%v: %w", edition, product, ver, err)
+		}
+		stale, err := compareStagedChecksums(staged, ga, newChecksums)
+		if err != nil {
+			return nil, fmt.Errorf("%v/%v/%v: %w", edition, product, ver, err)
+		}
+		staleChecksums = append(staleChecksums, stale...)
+		promotions = append(promotions, &promotion{staged: staged, ga: ga})
+	}
+	if len(promotions) == 0 {
+		return nil, fmt.Errorf("no %v %v-staging directory to promote", product, ver)
+	}
+
+	// Generation reads the GA checksums from the lockfile, which is only
+	// written once every directory is in place
+	for key, sum := range newChecksums {
+		checksums[key] = sum
+	}
+	discard := func() {
+		for key := range newChecksums {
+			delete(checksums, key)
+		}
+		for _, p := range promotions {
+			if p.workDir != "" {
+				os.RemoveAll(p.workDir)
+			}
+		}
+	}
+
+	for _, p := range promotions {
+		if err := p.generate(); err != nil {
+			discard()
+			return nil, fmt.Errorf("%v/%v/%v: %w", p.ga.Edition, product, ver, err)
+		}
+	}
+
+	moves := renames{}
+	for _, p := range promotions {
+		log.Printf("Moving %s to %s", p.staged.targetDir(), p.ga.targetDir())
+		err := moves.rename(p.staged.targetDir(), filepath.Join(p.workDir, "staged"))
+		if err == nil {
+			err = moves.rename(filepath.Join(p.workDir, "ga"), p.ga.targetDir())
+		}
+		if err != nil {
+			moves.undo()
+			discard()
+			return nil, err
+		}
+	}
+
+	for _, key := range staleChecksums {
+		delete(checksums, key)
+	}
+	if err := checksums.save(checksumsFile); err != nil {
+		moves.undo()
+		discard()
+		return nil, err
+	}
+	for _, p := range promotions {
+		if err := os.RemoveAll(p.workDir); err != nil {
+			return nil, err
+		}
+	}
+
+	variants := []DockerfileVariant{}
+	for _, p := range promotions {
+		variants = append(variants, p.ga)
+	}
+	if err := writeBakeFile(); err != nil {
+		return nil, err
+	}
+	return releaseTags(variants)
+}
+
+// generate copies the staging directory into a new work directory, next
+// to it, and generates the GA directory there
+func (p *promotion) generate() error {
+	workDir, err := os.MkdirTemp(filepath.Dir(p.staged.targetDir()), ".promote-"+p.ga.imageVersion()+"-")
+	if err != nil {
+		return err
+	}
+	p.workDir = workDir
+
+	ga := p.ga
+	ga.OutputDir = filepath.Join(workDir, "ga")
+	if err := CopyDir(p.staged.targetDir(), ga.OutputDir); err != nil {
+		return err
+	}
+	_, err = generateVariant(ga, false)
+	return err
+}
+
+// renames records the directories renamed so far, so that they can be
+// put back
+type renames [][2]string
+
+func (r *renames) rename(from, to string) error {
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	*r = append(*r, [2]string{from, to})
+	return nil
+}
+
+// undo reverses the renames, latest first
+func (r renames) undo() {
+	for i := len(r) - 1; i >= 0; i-- {
+		if err := os.Rename(r[i][1], r[i][0]); err != nil {
+			log.Printf("Error moving %s back to %s: %v", r[i][1], r[i][0], err)
+		}
+	}
+}
+
+// compareStagedChecksums checks that the checksum of each of ga's
+// packages, among sums, is the checksum the staged Dockerfile verifies
+// for that arch, so that the image being promoted is the one which was
+// tested. It returns the lockfile keys of the staged checksums, which are
+// no longer needed once ga replaces staged.
+func compareStagedChecksums(staged DockerfileVariant, ga DockerfileVariant, sums Checksums) ([]string, error) {
+	spec, err := ga.spec()
+	if err != nil {
+		return nil, err
+	}
+	if !spec.Checksums || len(spec.PackageFile) == 0 {
+		return nil, nil
+	}
+
+	stagedSums, err := dockerfileChecksums(staged.dockerfile(), staged.Arches)
+	if err != nil {
+		return nil, err
+	}
+	stale := []string{}
+	for _, arch := range ga.Arches {
+		stagedSum := stagedSums[arch]
+		if !sha256Pattern.MatchString(stagedSum) {
+			return nil, fmt.Errorf("%s does not verify the checksum of the %v package", staged.dockerfile(), arch)
+		}
+		if gaSum := sums[ga.checksumKey(arch)]; gaSum != stagedSum {
+			return nil, fmt.Errorf("GA package for %v has checksum %s, but the staged Dockerfile verifies %s", arch, gaSum, stagedSum)
+		}
+		if _, ok := checksums[staged.checksumKey(arch)]; ok {
+			stale = append(stale, staged.checksumKey(arch))
+		}
+	}
+	return stale, nil
+}
+
+var (
+	// dockerfileArchCase matches the start of an arch's branch of the
+	// case statement in a multi-arch Dockerfile, eg. 'arm64') \
+	dockerfileArchCase = regexp.MustCompile(`^'([a-z0-9_]+)'\)`)
+	// dockerfileSHA256 matches the checksum a Dockerfile verifies, which
+	// is named after the product, eg. CB_SHA256=... or ARG SGW_SHA256=...
+	dockerfileSHA256 = regexp.MustCompile(`^(ARG )?[A-Z_]+_SHA256=([^ \\]*)`)
+)
+
+// dockerfileChecksums returns the package checksum, by arch, which the
+// generated Dockerfile filename verifies. A multi-arch Dockerfile sets
+// one in the case branch of each arch; otherwise a single ARG applies to
+// every one of arches. Checksums which are skipped are empty.
+func dockerfileChecksums(filename string, arches []Arch) (map[Arch]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	byArch := map[Arch]string{}
+	argSum, hasArg := "", false
+	caseArch := Arch("")
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if m := dockerfileArchCase.FindStringSubmatch(line); m != nil {
+			caseArch = Arch(m[1])
+			continue
+		}
+		m := dockerfileSHA256.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if m[1] != "" {
+			argSum, hasArg = m[2], true
+		} else if caseArch != "" {
+			byArch[caseArch] = m[2]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(byArch) == 0 && hasArg {
+		for _, arch := range arches {
+			byArch[arch] = argSum
+		}
+	}
+	return byArch, nil
+}
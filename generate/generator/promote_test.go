@@ -0,0 +1,203 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stage generates the VERSION-staging directory of a product, whose
+// Dockerfile verifies the checksums in the lockfile, or else those
+// published in packages
+func stage(t *testing.T, tree string, edition Edition, product Product, ver string) string {
+	t.Helper()
+	dir := filepath.Join(tree, string(edition), string(product), ver+"-staging")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := generateOneDockerfile(edition, product, ver+"-staging", dir, nil, false, false); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// assertUnchanged checks that a failed promotion left the
+// staging directories and the lockfile as they were, with nothing next
+// to the staging directories
+func assertUnchanged(t *testing.T, stagedDirs []string, lockfile Checksums) {
+	t.Helper()
+	for _, dir := range stagedDirs {
+		if _, err := os.Stat(filepath.Join(dir, "Dockerfile")); err != nil {
+			t.Errorf("staging directory was changed: %v", err)
+		}
+		entries, err := os.ReadDir(filepath.Dir(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), "-staging") {
+				t.Errorf("%s was left in %s", entry.Name(), filepath.Dir(dir))
+			}
+		}
+	}
+	if !reflect.DeepEqual(checksums, lockfile) {
+		t.Errorf("lockfile = %v, want %v", checksums, lockfile)
+	}
+	if _, err := os.Stat(checksumsFile); !os.IsNotExist(err) {
+		t.Errorf("lockfile was written: %v", err)
+	}
+}
+
+func TestPromoteRelease(t *testing.T) {
+	tree, packages := withScratchTree(t)
+	sum := publish(t, packages, "releases/couchbase-edge-server/1.2.0/couchbase-edge-server_1.2.0_amd64.deb")
+	checksums["couchbase-edge-server/enterprise/1.2.0-staging/amd64"] = sum
+	stagedDir := stage(t, tree, EditionEnterprise, "couchbase-edge-server", "1.2.0")
+
+	tags, err := promoteRelease("couchbase-edge-server", "1.2.0", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"couchbase/edge-server:1.2.0", "couchbase/edge-server:1.2", "couchbase/edge-server:latest"}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %q, want %q", tags, want)
+	}
+	if _, err := os.Stat(stagedDir); !os.IsNotExist(err) {
+		t.Errorf("staging directory still exists: %v", err)
+	}
+	productDir := filepath.Join(tree, "enterprise", "couchbase-edge-server")
+	entries, err := os.ReadDir(productDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "1.2.0" {
+		t.Errorf("%s contains %v, want only 1.2.0", productDir, entries)
+	}
+	dockerfile, err := os.ReadFile(filepath.Join(productDir, "1.2.0", "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(dockerfile), "https://packages.couchbase.com/releases/couchbase-edge-server/1.2.0") {
+		t.Errorf("promoted Dockerfile does not use the production release URL:\n%s", dockerfile)
+	}
+	wantChecksums := Checksums{"couchbase-edge-server/enterprise/1.2.0/amd64": sum}
+	if !reflect.DeepEqual(checksums, wantChecksums) {
+		t.Errorf("lockfile = %v, want %v", checksums, wantChecksums)
+	}
+}
+
+func TestPromoteReleaseChecksumMismatch(t *testing.T) {
+	tree, packages := withScratchTree(t)
+	published := publish(t, packages, "releases/couchbase-edge-server/1.2.0/couchbase-edge-server_1.2.0_amd64.deb")
+	staged := strings.Repeat("0", 64)
+	checksums["couchbase-edge-server/enterprise/1.2.0-staging/amd64"] = staged
+	stagedDir := stage(t, tree, EditionEnterprise, "couchbase-edge-server", "1.2.0")
+
+	// What counts is the checksum the staged Dockerfile verifies, not the
+	// one in the lockfile
+	checksums["couchbase-edge-server/enterprise/1.2.0-staging/amd64"] = published
+	_, err := promoteRelease("couchbase-edge-server", "1.2.0", "")
+	if err == nil || !strings.Contains(err.Error(), "staged Dockerfile verifies "+staged) {
+		t.Fatalf("promoteRelease with a different GA checksum: got error %v", err)
+	}
+	assertUnchanged(t, []string{stagedDir},
+		Checksums{"couchbase-edge-server/enterprise/1.2.0-staging/amd64": published})
+}
+
+func TestPromoteReleaseRollback(t *testing.T) {
+	tree, packages := withScratchTree(t)
+	stagedDirs := []string{}
+	for _, edition := range []Edition{EditionCommunity, EditionEnterprise} {
+		for _, arch := range []Arch{Archamd64, Archarm64} {
+			publish(t, packages, "releases/8.0.2/couchbase-server-"+string(edition)+"_8.0.2-linux_"+string(arch)+".deb")
+		}
+		stagedDirs = append(stagedDirs, stage(t, tree, edition, "couchbase-server", "8.0.2"))
+	}
+
+	// The enterprise edition cannot be generated, so the community one,
+	// which can, must not be promoted either
+	settings := VersionSettings{Template: "Dockerfile.missing"}
+	if err := settings.save(filepath.Join(stagedDirs[1], settingsFilename)); err != nil {
+		t.Fatal(err)
+	}
+	_, err := promoteRelease("couchbase-server", "8.0.2", "")
+	if err == nil || !strings.Contains(err.Error(), "Dockerfile.missing") {
+		t.Fatalf("promoteRelease with a missing template: got error %v", err)
+	}
+	assertUnchanged(t, stagedDirs, Checksums{})
+}
+
+func TestDockerfileChecksums(t *testing.T) {
+	tests := []struct {
+		golden string
+		arches []Arch
+		want   map[Arch]string
+	}{
+		{
+			"enterprise/couchbase-server/7.6.2", []Arch{Archamd64, Archarm64},
+			map[Arch]string{
+				Archarm64: "b46203cebe7950dee04276fcf1e4d58f7e5fab5144dcd979f63e1c0c44a55791",
+				Archamd64: "c9e0c64648161cade062f1c640da39332e7f1e6bc73dffb682096c0cd286655d",
+			},
+		},
+		{
+			"enterprise/couchbase-server/6.6.0", []Arch{Archamd64},
+			map[Arch]string{Archamd64: "f0a02240d4f739a8a20234e75f5ab791a0f3fce12435389b5bc8dfc7b3302331"},
+		},
+		{
+			"enterprise/sync-gateway/4.0.0", []Arch{Archamd64, Archarm64},
+			map[Arch]string{
+				Archarm64: "0858a7468fbd166ae115e67838bd0254e0328698c4700e8d3908282924f5bd9d",
+				Archamd64: "cbcc5a8ca482ab720c67f4f217891794c5fc6e35ddae7ab94891fd8dc8da0d17",
+			},
+		},
+		{
+			"enterprise/couchbase-edge-server/1.0.0", []Arch{Archamd64},
+			map[Arch]string{Archamd64: "21f3a370cfa922d65f291593b1b1fbd58adccc8739b894e3a8913286efceae1b"},
+		},
+	}
+	for _, test := range tests {
+		got, err := dockerfileChecksums(filepath.Join("testdata", "golden", test.golden, "Dockerfile"), test.arches)
+		if err != nil {
+			t.Errorf("%s: %v", test.golden, err)
+		} else if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: checksums = %v, want %v", test.golden, got, test.want)
+		}
+	}
+}
+
+func TestRenamesUndo(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"staged", "generated"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	moves := renames{}
+	if err := moves.rename(filepath.Join(dir, "staged"), filepath.Join(dir, "aside")); err != nil {
+		t.Fatal(err)
+	}
+	if err := moves.rename(filepath.Join(dir, "generated"), filepath.Join(dir, "ga")); err != nil {
+		t.Fatal(err)
+	}
+	if err := moves.rename(filepath.Join(dir, "missing"), filepath.Join(dir, "ga2")); err == nil {
+		t.Fatal("renaming a missing directory succeeded")
+	}
+	moves.undo()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := []string{"generated", "staged"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("after undo, directory contains %v, want %v", names, want)
+	}
+}
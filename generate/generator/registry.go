@@ -0,0 +1,594 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Registry is the declarative description of every product the generator
+// knows about, loaded from generate/products.json. Adding a new product
+// image should only require a new entry here plus its templates and
+// resources.
+type Registry struct {
+	Editions []Edition `json:"editions"`
+	// StrictBaseImages makes it an error to generate a version which only
+	// the catch-all rule of an ubuntu or baseImage table applies to,
+	// rather than logging a warning and using it
+	StrictBaseImages bool            `json:"strictBaseImages"`
+	ReleaseHosts     ReleaseHosts    `json:"releaseHosts"`
+	Library          LibrarySettings `json:"library"`
+	Products         []*ProductSpec  `json:"products"`
+}
+
+// ReleaseHosts are the base URLs under which packages are published.
+type ReleaseHosts struct {
+	Production string `json:"production"`
+	Staging    string `json:"staging"`
+}
+
+// ProductSpec describes how to turn a product version into a
+// DockerfileVariant and the parameters for its template.
+//
+// String values in rules and params are themselves Go templates, which
+// are rendered with the helpers from DockerfileVariant.funcs().
+type ProductSpec struct {
+	Name Product `json:"name"`
+	// Image is the Docker Hub repository, eg. couchbase/server
+	Image string `json:"image"`
+	// Tags are the tags each version directory's image is published
+	// under. Tags which render as empty are dropped.
+	Tags []string `json:"tags"`
+	// Editions lists the editions the product is released in, if not
+	// all of the registry's editions
+	Editions []Edition `json:"editions"`
+	// VersionAliases maps a directory version to the real version
+	// which should be downloaded, eg. 7.0.3 -> 7.0.3-MP1
+	VersionAliases map[string]string `json:"versionAliases"`
+	// VersionSubstitutions replaces the version seen by the
+	// versionWithSubstitutions helper (first matching rule), eg. with
+	// the feature branch a build was made from
+	VersionSubstitutions Rules `json:"versionSubstitutions"`
+	// Templates selects the template filename (first matching rule)
+	Templates Rules `json:"templates"`
+	// Arches lists the supported architectures (every matching rule)
+	Arches Rules `json:"arches"`
+	// Ubuntu selects the Ubuntu release used as a base (first matching rule)
+	Ubuntu Rules `json:"ubuntu"`
+	// BaseImage selects the FROM image (first matching rule)
+	BaseImage Rules `json:"baseImage"`
+	// BasedOn names the product whose image this product is built
+	// from, and whose Ubuntu rules therefore also apply to it
+	BasedOn Product `json:"basedOn"`
+	// ReleaseHosts overrides the registry-wide release hosts, if set
+	ReleaseHosts *ReleaseHosts `json:"releaseHosts"`
+	// ReleaseIndex is where the discover command finds the product's
+	// published versions, if it has any packages of its own
+	ReleaseIndex *ReleaseIndex `json:"releaseIndex"`
+	// ReleaseURL is the directory containing the packages for a version
+	ReleaseURL string `json:"releaseUrl"`
+	// PackageFile selects the package filename (first matching rule)
+	PackageFile Rules `json:"packageFile"`
+	// PackageArches maps Docker's architecture names to those used in
+	// package filenames, where they differ (eg. amd64 -> x86_64)
+	PackageArches map[Arch]string `json:"packageArches"`
+	// Checksums is true if the product's packages are verified against
+	// the checksum lockfile
+	Checksums bool `json:"checksums"`
+	// Exclusions select the version directories which are not generated
+	Exclusions Exclusions `json:"exclusions"`
+	// Params are the values handed to the Dockerfile template
+	Params map[string]Param `json:"params"`
+}
+
+// ReleaseIndex locates the list of a product's published versions. URL
+// is a template which may use {{ releaseHost }} and {{ product }}. Format
+// is "html" (the default), for a directory listing linking to a
+// subdirectory per version, or "json", for an array of version strings.
+type ReleaseIndex struct {
+	URL    string `json:"url"`
+	Format string `json:"format"`
+}
+
+// Rule yields Value for any version satisfying the (optional) Versions
+// constraint and matching the (optional) Match regular expression.
+type Rule struct {
+	Versions string `json:"versions"`
+	Match    string `json:"match"`
+	Value    string `json:"value"`
+
+	constraint VersionConstraint
+	pattern    *regexp.Regexp
+}
+
+type Rules []*Rule
+
+// Param is a template parameter. In products.json it is either a plain
+// string, or an object {"type": "bool", "value": "..."} for parameters
+// which must not be handed to the template as a string.
+type Param struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+var registry *Registry
+
+func (p *Param) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		p.Type = "string"
+		p.Value = s
+		return nil
+	}
+
+	type param Param
+	var full param
+	if err := json.Unmarshal(data, &full); err != nil {
+		return err
+	}
+	*p = Param(full)
+	if p.Type == "" {
+		p.Type = "string"
+	}
+	return nil
+}
+
+// loadRegistry reads and validates the product registry at filename
+func loadRegistry(filename string) (*Registry, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var reg Registry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&reg); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	if err := reg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	return &reg, nil
+}
+
+func (reg *Registry) validate() error {
+	if len(reg.Editions) == 0 {
+		return fmt.Errorf("no editions defined")
+	}
+	if err := reg.ReleaseHosts.validate(); err != nil {
+		return err
+	}
+
+	seen := map[Product]bool{}
+	for _, spec := range reg.Products {
+		if spec.Name == "" {
+			return fmt.Errorf("product with no name")
+		}
+		if seen[spec.Name] {
+			return fmt.Errorf("product %v defined more than once", spec.Name)
+		}
+		seen[spec.Name] = true
+
+		if spec.Image == "" {
+			return fmt.Errorf("product %v: no image", spec.Name)
+		}
+		for _, edition := range spec.Editions {
+			if !reg.hasEdition(edition) {
+				return fmt.Errorf("product %v: unknown edition %v", spec.Name, edition)
+			}
+		}
+		if len(spec.Templates) == 0 {
+			return fmt.Errorf("product %v: no templates", spec.Name)
+		}
+		if len(spec.Arches) == 0 {
+			return fmt.Errorf("product %v: no arches", spec.Name)
+		}
+		if len(spec.BaseImage) == 0 {
+			return fmt.Errorf("product %v: no baseImage", spec.Name)
+		}
+		for _, arch := range spec.arches() {
+			if _, ok := reg.Library.Architectures[arch]; !ok {
+				return fmt.Errorf("product %v: arch %v has no library architecture", spec.Name, arch)
+			}
+		}
+
+		for name, rules := range map[string]Rules{
+			"versionSubstitutions": spec.VersionSubstitutions,
+			"templates":            spec.Templates,
+			"arches":               spec.Arches,
+			"ubuntu":               spec.Ubuntu,
+			"baseImage":            spec.BaseImage,
+			"packageFile":          spec.PackageFile,
+		} {
+			if err := rules.compile(); err != nil {
+				return fmt.Errorf("product %v: %s: %v", spec.Name, name, err)
+			}
+		}
+
+		if err := spec.Exclusions.compile(); err != nil {
+			return fmt.Errorf("product %v: exclusions: %v", spec.Name, err)
+		}
+
+		for name, rules := range map[string]Rules{
+			"ubuntu":    spec.Ubuntu,
+			"baseImage": spec.BaseImage,
+		} {
+			if err := rules.validateRanges(); err != nil {
+				return fmt.Errorf("product %v: %s: %v", spec.Name, name, err)
+			}
+		}
+
+		if spec.ReleaseHosts != nil {
+			if err := spec.ReleaseHosts.validate(); err != nil {
+				return fmt.Errorf("product %v: %v", spec.Name, err)
+			}
+		}
+
+		if index := spec.ReleaseIndex; index != nil {
+			if index.URL == "" {
+				return fmt.Errorf("product %v: releaseIndex: no url", spec.Name)
+			}
+			if index.Format != "" && index.Format != "html" && index.Format != "json" {
+				return fmt.Errorf("product %v: releaseIndex: unknown format %q", spec.Name, index.Format)
+			}
+		}
+
+		for key, param := range spec.Params {
+			if param.Type != "string" && param.Type != "bool" {
+				return fmt.Errorf("product %v: param %s: unknown type %q", spec.Name, key, param.Type)
+			}
+		}
+	}
+
+	for _, spec := range reg.Products {
+		if spec.BasedOn == "" {
+			continue
+		}
+		base, ok := reg.product(spec.BasedOn)
+		if !ok {
+			return fmt.Errorf("product %v: basedOn: unknown product %v", spec.Name, spec.BasedOn)
+		}
+		if len(spec.Ubuntu) != 0 {
+			return fmt.Errorf("product %v: has both ubuntu and basedOn", spec.Name)
+		}
+		if len(base.Ubuntu) == 0 {
+			return fmt.Errorf("product %v: basedOn: %v has no ubuntu rules", spec.Name, spec.BasedOn)
+		}
+	}
+
+	return nil
+}
+
+// product returns the registry entry for the named product
+func (reg *Registry) product(name Product) (*ProductSpec, bool) {
+	for _, spec := range reg.Products {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return nil, false
+}
+
+// productNames returns the names of all registered products, in
+// registry order
+func (reg *Registry) productNames() []Product {
+	names := []Product{}
+	for _, spec := range reg.Products {
+		names = append(names, spec.Name)
+	}
+	return names
+}
+
+// hasEdition returns true if edition is one of the registry's editions
+func (reg *Registry) hasEdition(edition Edition) bool {
+	for _, e := range reg.Editions {
+		if e == edition {
+			return true
+		}
+	}
+	return false
+}
+
+// editions returns the editions the product is released in
+func (spec *ProductSpec) editions() []Edition {
+	if len(spec.Editions) != 0 {
+		return spec.Editions
+	}
+	return registry.Editions
+}
+
+// arches returns every arch which any of the product's arches rules
+// yields, in the order the rules give them
+func (spec *ProductSpec) arches() []Arch {
+	arches := []Arch{}
+	seen := map[Arch]bool{}
+	for _, rule := range spec.Arches {
+		if arch := Arch(rule.Value); !seen[arch] {
+			seen[arch] = true
+			arches = append(arches, arch)
+		}
+	}
+	return arches
+}
+
+// packageArch returns the name for arch used in package filenames
+func (spec *ProductSpec) packageArch(arch Arch) Arch {
+	if name, ok := spec.PackageArches[arch]; ok {
+		return Arch(name)
+	}
+	return arch
+}
+
+// validate checks that packages, and the checksums they are verified
+// against, are downloaded over HTTPS
+func (hosts ReleaseHosts) validate() error {
+	for _, host := range []string{hosts.Production, hosts.Staging} {
+		if !strings.HasPrefix(host, "https://") {
+			return fmt.Errorf("release host %q is not an https:// URL", host)
+		}
+	}
+	return nil
+}
+
+// releaseHosts returns the release hosts for this product
+func (spec *ProductSpec) releaseHosts() ReleaseHosts {
+	if spec.ReleaseHosts != nil {
+		return *spec.ReleaseHosts
+	}
+	return registry.ReleaseHosts
+}
+
+func (rules Rules) compile() error {
+	for _, rule := range rules {
+		if rule.Versions != "" {
+			constraint, err := ParseConstraint(rule.Versions)
+			if err != nil {
+				return err
+			}
+			rule.constraint = constraint
+		}
+		if rule.Match != "" {
+			pattern, err := regexp.Compile(rule.Match)
+			if err != nil {
+				return fmt.Errorf("bad match %q: %v", rule.Match, err)
+			}
+			rule.pattern = pattern
+		}
+	}
+	return nil
+}
+
+// matches returns true if the rule applies to the given version
+func (rule *Rule) matches(ver string) (bool, error) {
+	if rule.pattern != nil && !rule.pattern.MatchString(ver) {
+		return false, nil
+	}
+	if rule.constraint != nil {
+		v, err := ParseVersion(ver)
+		if err != nil {
+			return false, err
+		}
+		if !rule.constraint.Check(v) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// first returns the value of the first rule matching the given version
+func (rules Rules) first(ver string) (string, bool, error) {
+	for _, rule := range rules {
+		ok, err := rule.matches(ver)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			return rule.Value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// isFallback returns true if rule is a catch-all following version-range
+// rules, so it applies to versions nobody has explicitly mapped yet
+func (rules Rules) isFallback(rule *Rule) bool {
+	if rule.constraint != nil || rule.pattern != nil {
+		return false
+	}
+	for _, r := range rules {
+		if r.constraint != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRanges checks that the version ranges of rules without a match
+// pattern neither overlap nor leave gaps between them. It is used for
+// the base image tables, where a version falling between two ranges
+// would silently get the catch-all value.
+func (rules Rules) validateRanges() error {
+	type ranged struct {
+		versions string
+		interval versionInterval
+	}
+	ranges := []ranged{}
+	for _, rule := range rules {
+		if rule.constraint == nil || rule.pattern != nil {
+			continue
+		}
+		interval, err := rule.constraint.interval()
+		if err != nil {
+			return fmt.Errorf("%q: %v", rule.Versions, err)
+		}
+		ranges = append(ranges, ranged{rule.Versions, interval})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return compareLower(ranges[i].interval, ranges[j].interval) < 0
+	})
+	for i := 1; i < len(ranges); i++ {
+		prev, next := ranges[i-1], ranges[i]
+		if prev.interval.overlaps(next.interval) {
+			return fmt.Errorf("%q overlaps %q", prev.versions, next.versions)
+		}
+		if !prev.interval.adjoins(next.interval) {
+			return fmt.Errorf("gap between %q and %q", prev.versions, next.versions)
+		}
+	}
+	return nil
+}
+
+// baseImagePolicy returns the value of the first of a base image table's
+// rules (table is "ubuntu" or "baseImage") which matches ver. If only the
+// catch-all rule matches, that is reported, and is an error when the
+// registry's strictBaseImages policy is set.
+func (rules Rules) baseImagePolicy(product Product, table string, ver string) (string, bool, error) {
+	for _, rule := range rules {
+		ok, err := rule.matches(ver)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			continue
+		}
+		if rules.isFallback(rule) {
+			if registry.StrictBaseImages {
+				return "", false, fmt.Errorf("%v %v has no explicit %s rule in products.json", product, ver, table)
+			}
+			log.Printf("Warning: %v %v has no explicit %s rule, using %q", product, ver, table, rule.Value)
+		}
+		return rule.Value, true, nil
+	}
+	return "", false, nil
+}
+
+// all returns the values of every rule matching the given version
+func (rules Rules) all(ver string) ([]string, error) {
+	values := []string{}
+	for _, rule := range rules {
+		ok, err := rule.matches(ver)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			values = append(values, rule.Value)
+		}
+	}
+	return values, nil
+}
+
+// render evaluates one of the registry's templated strings for a variant.
+// arch is the architecture seen by the "arch" helper.
+func (variant DockerfileVariant) render(text string, arch Arch) (string, error) {
+	tmpl, err := template.New("registry").Funcs(variant.funcs(arch)).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, nil); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// funcs returns the helpers available both to the registry's templated
+// strings and to the Dockerfile templates, so that version-specific
+// differences can be written where they are used rather than as a new
+// param, eg.
+//
+//	{{ if versionIn ">= 7.0, < 7.6" }}...{{ end }}
+//	{{ if hasArch "arm64" }}...{{ end }}
+//	{{ join " " .EXTRA_PACKAGES }}
+//
+// Like the rules, version comparisons only consider the release numbers,
+// so 7.6.0-beta is at least 7.6.0.
+func (variant DockerfileVariant) funcs(arch Arch) template.FuncMap {
+	return template.FuncMap{
+		"product":                  func() Product { return variant.Product },
+		"edition":                  func() Edition { return variant.Edition },
+		"version":                  func() string { return variant.Version },
+		"targetVersion":            func() string { return variant.TargetVersion },
+		"imageVersion":             variant.imageVersion,
+		"versionWithSubstitutions": variant.VersionWithSubstitutions,
+		"staging":                  func() bool { return variant.IsStaging },
+		"arch":                     func() Arch { return arch },
+		"arches":                   func() []Arch { return variant.Arches },
+		"multiarch":                func() bool { return len(variant.Arches) > 1 },
+		"hasArch":                  func(arch string) bool { return variant.hasArch(Arch(arch)) },
+		"versionIn":                variant.versionCheck,
+		"versionAtLeast": func(ver string) (bool, error) {
+			return variant.versionCheck(">= " + ver)
+		},
+		"ubuntuVersion": variant.ubuntuVersion,
+		"baseImage":     variant.dockerBaseImage,
+		"releaseHost":   variant.releaseHost,
+		"releaseURL":    variant.releaseURL,
+		"packageFile":   variant.packageFile,
+		"packageURL":    variant.packageURL,
+		"sha256":        variant.getSHA256,
+		"skipChecksum":  variant.skipChecksum,
+		"join":          join,
+	}
+}
+
+// join concatenates the elements of list, which may be a list of strings
+// or of any other values (eg. from a JSON list), separated by sep
+func join(sep string, list any) (string, error) {
+	switch list := list.(type) {
+	case []string:
+		return strings.Join(list, sep), nil
+	case []Arch:
+		items := []string{}
+		for _, arch := range list {
+			items = append(items, string(arch))
+		}
+		return strings.Join(items, sep), nil
+	case []any:
+		items := []string{}
+		for _, item := range list {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, sep), nil
+	default:
+		return "", fmt.Errorf("join: %T is not a list", list)
+	}
+}
+
+// params renders the template parameters declared for this variant's
+// product
+func (variant DockerfileVariant) params() (map[string]any, error) {
+	spec, err := variant.spec()
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]any{}
+	for key, param := range spec.Params {
+		value, err := variant.render(param.Value, Archgeneric)
+		if err != nil {
+			return nil, fmt.Errorf("param %s: %w", key, err)
+		}
+		switch param.Type {
+		case "bool":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("param %s: %v", key, err)
+			}
+			params[key] = b
+		default:
+			params[key] = value
+		}
+	}
+	return params, nil
+}
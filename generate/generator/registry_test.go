@@ -0,0 +1,187 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func compiledRules(t *testing.T, versions ...string) Rules {
+	t.Helper()
+	rules := Rules{}
+	for _, v := range versions {
+		rules = append(rules, &Rule{Versions: v, Value: v})
+	}
+	if err := rules.compile(); err != nil {
+		t.Fatal(err)
+	}
+	return rules
+}
+
+func TestValidateRanges(t *testing.T) {
+	tests := []struct {
+		versions []string
+		err      string
+	}{
+		{[]string{">= 4.0, < 5.0", ">= 5.0, <= 6.0.0", ">= 6.0.1, < 7.0.0", ""}, ""},
+		{[]string{">= 7.6.0, <= 7.6.1", "< 7.6.0", "> 7.6.1"}, ""},
+		{[]string{"= 7.2.0", ">= 7.2.1"}, ""},
+		{[]string{">= 6.6.2, <= 7.1.6", ">= 7.2.0"}, "gap"},
+		{[]string{"< 7.0.0", "> 7.0.0"}, "gap"},
+		{[]string{"<= 7.2.5", ">= 7.2.5"}, "overlaps"},
+		{[]string{">= 7.0.0", ">= 8.0.0"}, "overlaps"},
+		{[]string{"!= 7.0.0"}, "not a version range"},
+		{[]string{">= 8.0.0, < 7.0.0"}, "matches no versions"},
+	}
+	for _, test := range tests {
+		err := compiledRules(t, test.versions...).validateRanges()
+		if test.err == "" && err != nil {
+			t.Errorf("%q: %v", test.versions, err)
+		} else if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+			t.Errorf("%q: got error %v, want %q", test.versions, err, test.err)
+		}
+	}
+}
+
+func TestBaseImagePolicy(t *testing.T) {
+	defer func(strict bool) { registry.StrictBaseImages = strict }(registry.StrictBaseImages)
+
+	rules := compiledRules(t, "< 8.0.0", "")
+
+	registry.StrictBaseImages = false
+	if value, ok, err := rules.baseImagePolicy("couchbase-server", "ubuntu", "9.0.0"); err != nil || !ok || value != "" {
+		t.Errorf("lenient policy = %q, %v, %v; want the catch-all rule", value, ok, err)
+	}
+
+	registry.StrictBaseImages = true
+	if value, _, err := rules.baseImagePolicy("couchbase-server", "ubuntu", "7.6.2"); err != nil || value != "< 8.0.0" {
+		t.Errorf("strict policy for a mapped version = %q, %v", value, err)
+	}
+	if _, _, err := rules.baseImagePolicy("couchbase-server", "ubuntu", "9.0.0"); err == nil {
+		t.Errorf("strict policy allowed an unmapped version")
+	}
+
+	// A table with only a catch-all rule maps every version explicitly
+	if _, _, err := compiledRules(t, "").baseImagePolicy("sync-gateway", "ubuntu", "9.0.0"); err != nil {
+		t.Errorf("strict policy rejected a single catch-all rule: %v", err)
+	}
+}
+
+func TestServerUbuntuVersions(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"6.6.2", "20.04"},
+		{"7.1.6", "20.04"},
+		{"7.1.7", "24.04"},
+		{"7.2.0", "22.04"},
+		{"7.2.6", "24.04"},
+		{"7.6.1", "22.04"},
+		{"7.6.2", "24.04"},
+		{"8.0.1", "24.04"},
+	}
+	for _, test := range tests {
+		variant, err := newVariant(EditionEnterprise, "couchbase-server", test.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := variant.render("{{ ubuntuVersion }}", Archgeneric)
+		if err != nil {
+			t.Errorf("%s: %v", test.version, err)
+		} else if got != test.want {
+			t.Errorf("%s: ubuntu %s, want %s", test.version, got, test.want)
+		}
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	variant, err := newVariant(EditionEnterprise, "couchbase-server", "7.6.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{`{{ versionAtLeast "7.6.0" }} {{ versionAtLeast "7.6.3" }}`, "true false"},
+		{`{{ versionIn ">= 7.0, < 7.6" }} {{ versionIn ">= 7.6, < 8.0" }}`, "false true"},
+		{`{{ if hasArch "arm64" }}arm64{{ end }}{{ if hasArch "s390x" }}s390x{{ end }}`, "arm64"},
+		{`{{ edition }}/{{ product }}/{{ version }}`, "enterprise/couchbase-server/7.6.2"},
+		{`{{ join "," arches }}`, "amd64,arm64"},
+		{`{{ join " " .LIST }}`, "bzip2 1 true"},
+	}
+	params := map[string]any{"LIST": []any{"bzip2", 1, true}}
+	for _, test := range tests {
+		tmpl, err := template.New("test").Funcs(variant.funcs(Archgeneric)).Parse(test.text)
+		if err != nil {
+			t.Errorf("%s: %v", test.text, err)
+			continue
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, params); err != nil {
+			t.Errorf("%s: %v", test.text, err)
+			continue
+		}
+		if out.String() != test.want {
+			t.Errorf("%s = %q, want %q", test.text, out.String(), test.want)
+		}
+	}
+
+	tmpl := template.Must(template.New("test").Funcs(variant.funcs(Archgeneric)).Parse(`{{ versionAtLeast "7.6-beta" }}`))
+	if err := tmpl.Execute(&strings.Builder{}, params); err == nil {
+		t.Errorf("versionAtLeast accepted a malformed version")
+	}
+}
+
+func TestVersionWithSubstitutions(t *testing.T) {
+	tests := []struct {
+		product Product
+		ver     string
+		want    string
+	}{
+		{"sync-gateway", "0.0.0-forestdb", "feature/forestdb"},
+		{"sync-gateway", "3.1.0", "3.1.0"},
+		{"couchbase-server", "0.0.0-forestdb", "0.0.0-forestdb"},
+	}
+	for _, test := range tests {
+		variant, err := newVariant(EditionCommunity, test.product, test.ver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := variant.VersionWithSubstitutions()
+		if err != nil {
+			t.Errorf("%v %v: %v", test.product, test.ver, err)
+		} else if got != test.want {
+			t.Errorf("%v %v: version = %q, want %q", test.product, test.ver, got, test.want)
+		}
+	}
+}
+
+func TestLibraryArchitectures(t *testing.T) {
+	reg, err := loadRegistry(filepath.Join(repoDir, "generate", "products.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(reg.Library.Architectures, Archarm64)
+	if err := reg.validate(); err == nil || !strings.Contains(err.Error(), "arm64") {
+		t.Errorf("registry with no library architecture for arm64: got error %v", err)
+	}
+}
+
+func TestReleaseHostsHTTPS(t *testing.T) {
+	reg, err := loadRegistry(filepath.Join(repoDir, "generate", "products.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, _ := reg.product("sync-gateway")
+	spec.ReleaseHosts = &ReleaseHosts{
+		Production: "http://packages.couchbase.com/releases",
+		Staging:    reg.ReleaseHosts.Staging,
+	}
+	if err := reg.validate(); err == nil || !strings.Contains(err.Error(), "http://packages.couchbase.com") {
+		t.Errorf("registry with a plain http release host: got error %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// settingsFilename is the name of the file in a version directory which
+// holds its VersionSettings
+const settingsFilename = ".generate.json"
+
+// VersionSettings are the generation settings of a single version
+// directory, which take precedence over the product registry. They are
+// honored by every form of the generator, so that a directory generated
+// with -t overrides in single mode is regenerated identically in bulk.
+type VersionSettings struct {
+	// Template replaces the template filename chosen by the registry
+	Template string `json:"template,omitempty"`
+	// Arches replaces the arches chosen by the registry
+	Arches []Arch `json:"arches,omitempty"`
+	// Overrides are template parameter overrides, as given by -t
+	Overrides map[string]any `json:"overrides,omitempty"`
+}
+
+// settingsFile returns the path of the settings file of a version
+// directory
+func settingsFile(edition Edition, product Product, ver string) string {
+	return path.Join(baseDir, string(edition), string(product), ver, settingsFilename)
+}
+
+// loadVersionSettings reads the settings file at filename, returning
+// empty settings if there is none
+func loadVersionSettings(filename string) (VersionSettings, error) {
+	settings := VersionSettings{}
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	decoder.UseNumber()
+	if err := decoder.Decode(&settings); err != nil {
+		return settings, fmt.Errorf("%s: %v", filename, err)
+	}
+	for key, value := range settings.Overrides {
+		settings.Overrides[key] = convertNumbers(value)
+	}
+	return settings, nil
+}
+
+// save writes the settings to filename
+func (settings VersionSettings) save(filename string) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// recordOverrides adds overrides to the settings of the version directory
+// of variant, if it is being generated in place, so that regenerating it
+// later reproduces the same Dockerfile. Overrides used to generate into
+// some other directory are a one-off and are not recorded.
+func recordOverrides(variant DockerfileVariant, overrides map[string]any) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	filename := settingsFile(variant.Edition, variant.Product, variant.imageVersion())
+	versionDir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return err
+	}
+	outputDir, err := filepath.Abs(variant.targetDir())
+	if err != nil {
+		return err
+	}
+	if versionDir != outputDir {
+		return nil
+	}
+
+	settings, err := loadVersionSettings(filename)
+	if err != nil {
+		return err
+	}
+	if settings.Overrides == nil {
+		settings.Overrides = map[string]any{}
+	}
+	for key, value := range overrides {
+		settings.Overrides[key] = value
+	}
+	return settings.save(filename)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVersionSettings(t *testing.T) {
+	tree, _ := withScratchTree(t)
+	dir := filepath.Join(tree, "enterprise", "couchbase-server", "7.6.2")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, arch := range []Arch{Archamd64, Archarm64} {
+		checksums["couchbase-server/enterprise/7.6.2/"+string(arch)] = strings.Repeat("0", 64)
+	}
+	settings := `{"arches": ["amd64"], "overrides": {"DOCKER_BASE_IMAGE": "ubuntu:22.04"}}`
+	if err := os.WriteFile(filepath.Join(dir, settingsFilename), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Single mode, in place: the -t override is recorded alongside the
+	// existing settings
+	err := generateOneDockerfile(EditionEnterprise, "couchbase-server", "7.6.2", dir,
+		map[string]any{"PROFILE": "analytics"}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := loadVersionSettings(filepath.Join(dir, settingsFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := VersionSettings{
+		Arches:    []Arch{Archamd64},
+		Overrides: map[string]any{"DOCKER_BASE_IMAGE": "ubuntu:22.04", "PROFILE": "analytics"},
+	}
+	if !reflect.DeepEqual(saved, want) {
+		t.Errorf("settings = %+v, want %+v", saved, want)
+	}
+	single, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bulk mode reproduces the same Dockerfile
+	if err := os.Remove(filepath.Join(dir, "Dockerfile")); err != nil {
+		t.Fatal(err)
+	}
+	if failed, err := generateAllDockerfiles(VersionDirFilter{}, false); err != nil || failed > 0 {
+		t.Fatalf("%d directories failed to generate: %v", failed, err)
+	}
+	bulk, err := os.ReadFile(filepath.Join(dir, "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bulk) != string(single) {
+		t.Errorf("bulk mode generated a different Dockerfile:\n%s", unifiedDiff("single", "bulk", string(single), string(bulk)))
+	}
+	for _, want := range []string{`echo "analytics"`, "FROM ubuntu:22.04", "ARG CB_SHA256="} {
+		if !strings.Contains(string(bulk), want) {
+			t.Errorf("Dockerfile does not contain %q", want)
+		}
+	}
+
+	// Overrides used to generate elsewhere are not recorded
+	err = generateOneDockerfile(EditionEnterprise, "couchbase-server", "7.6.2", t.TempDir(),
+		map[string]any{"PROFILE": "columnar"}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, err = loadVersionSettings(filepath.Join(dir, settingsFilename)); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Overrides["PROFILE"] != "analytics" {
+		t.Errorf("out-of-place override was recorded: %+v", saved)
+	}
+
+	// Nor are overrides which were not used, because the existing
+	// Dockerfile was kept
+	err = generateOneDockerfile(EditionEnterprise, "couchbase-server", "7.6.2", dir,
+		map[string]any{"PROFILE": "columnar"}, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, err = loadVersionSettings(filepath.Join(dir, settingsFilename)); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Overrides["PROFILE"] != "analytics" {
+		t.Errorf("override for a Dockerfile which was not regenerated was recorded: %+v", saved)
+	}
+}
+
+func TestLoadVersionSettingsErrors(t *testing.T) {
+	for _, settings := range []string{
+		`{"arches": "amd64"}`,
+		`{"overides": {"PROFILE": "analytics"}}`,
+	} {
+		filename := filepath.Join(t.TempDir(), settingsFilename)
+		if err := os.WriteFile(filename, []byte(settings), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadVersionSettings(filename); err == nil {
+			t.Errorf("%s was accepted", settings)
+		}
+	}
+}
+
+func TestVersionSettingsArches(t *testing.T) {
+	tree, _ := withScratchTree(t)
+	tests := []struct {
+		product Product
+		arches  string
+		err     bool
+	}{
+		{"couchbase-server", `["arm64"]`, false},
+		{"couchbase-server", `["s390x"]`, true},
+		// The registry only ever builds Edge Server for amd64
+		{"couchbase-edge-server", `["amd64", "arm64"]`, true},
+	}
+	for _, test := range tests {
+		dir := filepath.Join(tree, "enterprise", string(test.product), "1.0.0")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		settings := `{"arches": ` + test.arches + `}`
+		if err := os.WriteFile(filepath.Join(dir, settingsFilename), []byte(settings), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := newVariant(EditionEnterprise, test.product, "1.0.0")
+		if (err != nil) != test.err {
+			t.Errorf("%v with arches %s: got error %v, want error %v", test.product, test.arches, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// tagIndex knows the latest generally-available version of each product
+// and edition, overall and within each major.minor release, so that
+// floating tags such as latest, enterprise and 7.6 can be assigned.
+// Pre-releases (eg. -beta), build-number and -staging directories are
+// never given floating tags.
+type tagIndex struct {
+	latest      map[tagGroup]ProductVersion
+	latestMinor map[tagGroup]ProductVersion
+}
+
+// tagGroup is the set of directories a floating tag is chosen from.
+// Minor is empty for the product and edition as a whole.
+type tagGroup struct {
+	Edition Edition
+	Product Product
+	Minor   string
+}
+
+// newTagIndex builds the tag index for dirs, eg. every version directory
+// found by allVersionDirs()
+func newTagIndex(dirs []VersionDir) (*tagIndex, error) {
+	index := &tagIndex{
+		latest:      map[tagGroup]ProductVersion{},
+		latestMinor: map[tagGroup]ProductVersion{},
+	}
+	for _, dir := range dirs {
+		v, err := ParseVersion(dir.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", dir, err)
+		}
+		if !isGA(v) {
+			continue
+		}
+
+		group := tagGroup{Edition: dir.Edition, Product: dir.Product}
+		if latest, ok := index.latest[group]; !ok || v.Compare(latest) > 0 {
+			index.latest[group] = v
+		}
+		group.Minor = minorVersion(v)
+		if latest, ok := index.latestMinor[group]; !ok || v.Compare(latest) > 0 {
+			index.latestMinor[group] = v
+		}
+	}
+	return index, nil
+}
+
+// isGA returns true for versions which may be given floating tags
+func isGA(v ProductVersion) bool {
+	return v.Prerelease == "" && v.Build == 0 && !v.Staging
+}
+
+// minorVersion returns the major.minor release of v, eg. 7.6
+func minorVersion(v ProductVersion) string {
+	sections := []string{}
+	for i := 0; i < 2; i++ {
+		n := 0
+		if i < len(v.Release) {
+			n = v.Release[i]
+		}
+		sections = append(sections, strconv.Itoa(n))
+	}
+	return strings.Join(sections, ".")
+}
+
+// funcs returns the helpers for floating tags, for the given variant
+func (index *tagIndex) funcs(variant DockerfileVariant) template.FuncMap {
+	version := func() (ProductVersion, error) {
+		return ParseVersion(variant.imageVersion())
+	}
+	group := tagGroup{Edition: variant.Edition, Product: variant.Product}
+
+	return template.FuncMap{
+		// latest is true for the newest GA version of the product and
+		// edition
+		"latest": func() (bool, error) {
+			v, err := version()
+			if err != nil {
+				return false, err
+			}
+			latest, ok := index.latest[group]
+			return ok && isGA(v) && v.Compare(latest) == 0, nil
+		},
+		// latestInMinor is true for the newest GA version within its
+		// major.minor release
+		"latestInMinor": func() (bool, error) {
+			v, err := version()
+			if err != nil {
+				return false, err
+			}
+			minorGroup := group
+			minorGroup.Minor = minorVersion(v)
+			latest, ok := index.latestMinor[minorGroup]
+			return ok && isGA(v) && v.Compare(latest) == 0, nil
+		},
+		// minorVersion is the major.minor release, eg. 7.6
+		"minorVersion": func() (string, error) {
+			v, err := version()
+			if err != nil {
+				return "", err
+			}
+			return minorVersion(v), nil
+		},
+	}
+}
+
+// tagNames returns the tags (without the repository) this variant is
+// published under, according to its product's tags rules
+func (variant DockerfileVariant) tagNames(index *tagIndex) ([]string, error) {
+	spec, err := variant.spec()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	for _, tagTemplate := range spec.Tags {
+		tmpl, err := template.New("tag").
+			Funcs(variant.funcs(Archgeneric)).
+			Funcs(index.funcs(variant)).
+			Parse(tagTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("tag %q: %w", tagTemplate, err)
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, nil); err != nil {
+			return nil, fmt.Errorf("tag %q: %w", tagTemplate, err)
+		}
+		if tag := strings.TrimSpace(out.String()); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// tags returns the full image references (eg. couchbase/server:7.6.2)
+// this variant is published under
+func (variant DockerfileVariant) tags(index *tagIndex) ([]string, error) {
+	spec, err := variant.spec()
+	if err != nil {
+		return nil, err
+	}
+	names, err := variant.tagNames(index)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]string, len(names))
+	for i, name := range names {
+		tags[i] = spec.Image + ":" + name
+	}
+	return tags, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// checkOverridesUsed returns an error if any of overrides is not a
+// parameter which tmpl (or a partial) refers to, eg. because its name is
+// misspelled
+func checkOverridesUsed(tmpl *template.Template, overrides map[string]any) error {
+	used := map[string]bool{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			addFieldNames(t.Tree.Root, used)
+		}
+	}
+
+	unused := []string{}
+	for key := range overrides {
+		if !used[key] {
+			unused = append(unused, key)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return fmt.Errorf("template does not use overridden parameter(s) %s", strings.Join(unused, ", "))
+	}
+	return nil
+}
+
+// addFieldNames adds the names of the parameters node refers to, as .NAME
+// or $.NAME, to names
+func addFieldNames(node parse.Node, names map[string]bool) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, n := range node.Nodes {
+			addFieldNames(n, names)
+		}
+	case *parse.ActionNode:
+		addFieldNames(node.Pipe, names)
+	case *parse.IfNode:
+		addBranchFieldNames(&node.BranchNode, names)
+	case *parse.RangeNode:
+		addBranchFieldNames(&node.BranchNode, names)
+	case *parse.WithNode:
+		addBranchFieldNames(&node.BranchNode, names)
+	case *parse.TemplateNode:
+		addFieldNames(node.Pipe, names)
+	case *parse.PipeNode:
+		if node == nil {
+			return
+		}
+		for _, cmd := range node.Cmds {
+			addFieldNames(cmd, names)
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			addFieldNames(arg, names)
+		}
+	case *parse.ChainNode:
+		addFieldNames(node.Node, names)
+	case *parse.FieldNode:
+		names[node.Ident[0]] = true
+	case *parse.VariableNode:
+		if len(node.Ident) > 1 && node.Ident[0] == "$" {
+			names[node.Ident[1]] = true
+		}
+	}
+}
+
+func addBranchFieldNames(node *parse.BranchNode, names map[string]bool) {
+	addFieldNames(node.Pipe, names)
+	addFieldNames(node.List, names)
+	addFieldNames(node.ElseList, names)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestCheckOverridesUsed(t *testing.T) {
+	tmpl := template.Must(template.New("docker").Parse(
+		`FROM {{ .DOCKER_BASE_IMAGE }}{{ if .PROFILE }}{{ template "profile" . }}{{ end }}` +
+			`{{ range $.PACKAGES }}{{ . }}{{ end }}`,
+	))
+	template.Must(tmpl.New("profile").Parse(`{{ with .CB_VERSION }}{{ . }}{{ else }}{{ .CB_PACKAGE }}{{ end }}`))
+
+	used := map[string]any{
+		"DOCKER_BASE_IMAGE": "ubuntu:24.04",
+		"PROFILE":           "columnar",
+		"PACKAGES":          []any{"bzip2"},
+		"CB_VERSION":        "7.6.2",
+		"CB_PACKAGE":        "x.deb",
+	}
+	if err := checkOverridesUsed(tmpl, used); err != nil {
+		t.Errorf("used overrides: %v", err)
+	}
+
+	err := checkOverridesUsed(tmpl, map[string]any{"PROFIEL": "columnar", "PROFILE": "columnar"})
+	if err == nil || !strings.Contains(err.Error(), "PROFIEL") || strings.Contains(err.Error(), "PROFILE") {
+		t.Errorf("misspelled override: got error %v", err)
+	}
+}
+
+func TestGenerateStrict(t *testing.T) {
+	variant, err := newVariant(EditionEnterprise, "couchbase-server", "7.6.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	variant.OutputDir = t.TempDir()
+
+	variant.TemplateOverrides = map[string]any{"PROFIEL": "columnar"}
+	if err := generateDockerfile(variant); err == nil {
+		t.Errorf("unused override was accepted")
+	}
+	if _, err := os.Stat(filepath.Join(variant.OutputDir, "Dockerfile")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Dockerfile was written despite the error: %v", err)
+	}
+
+	variant.TemplateOverrides = map[string]any{"PROFILE": "columnar"}
+	if err := generateDockerfile(variant); err != nil {
+		t.Fatal(err)
+	}
+	dockerfile, err := os.ReadFile(filepath.Join(variant.OutputDir, "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(dockerfile), `echo "columnar"`) {
+		t.Errorf("PROFILE override was not applied")
+	}
+}
@@ -0,0 +1,247 @@
+# Generated by generate/generator; do not edit.
+
+group "default" {
+  targets = [
+    "couchbase-server",
+    "sync-gateway",
+    "server-sandbox",
+    "couchbase-columnar",
+    "couchbase-edge-server",
+    "enterprise-analytics",
+  ]
+}
+
+group "couchbase-server" {
+  targets = [
+    "couchbase-server-community",
+    "couchbase-server-enterprise",
+  ]
+}
+
+group "couchbase-server-community" {
+  targets = [
+    "couchbase-server-community-7_6_2",
+  ]
+}
+
+group "couchbase-server-enterprise" {
+  targets = [
+    "couchbase-server-enterprise-4_6_5",
+    "couchbase-server-enterprise-5_5_0",
+    "couchbase-server-enterprise-6_6_0",
+    "couchbase-server-enterprise-6_6_2",
+    "couchbase-server-enterprise-7_0_3",
+    "couchbase-server-enterprise-7_1_0",
+    "couchbase-server-enterprise-7_2_4",
+    "couchbase-server-enterprise-7_6_1",
+    "couchbase-server-enterprise-7_6_2",
+    "couchbase-server-enterprise-8_0_0-staging",
+  ]
+}
+
+group "sync-gateway" {
+  targets = [
+    "sync-gateway-community",
+    "sync-gateway-enterprise",
+  ]
+}
+
+group "sync-gateway-community" {
+  targets = [
+    "sync-gateway-community-1_1_0-forestdb_bucket",
+    "sync-gateway-community-1_5_0",
+    "sync-gateway-community-2_0_0-devbuild",
+  ]
+}
+
+group "sync-gateway-enterprise" {
+  targets = [
+    "sync-gateway-enterprise-3_0_3",
+    "sync-gateway-enterprise-3_0_4",
+    "sync-gateway-enterprise-4_0_0",
+  ]
+}
+
+group "server-sandbox" {
+  targets = [
+    "server-sandbox-enterprise",
+  ]
+}
+
+group "server-sandbox-enterprise" {
+  targets = [
+    "server-sandbox-enterprise-7_0_5",
+    "server-sandbox-enterprise-7_1_0",
+  ]
+}
+
+group "couchbase-columnar" {
+  targets = [
+    "couchbase-columnar-enterprise",
+  ]
+}
+
+group "couchbase-columnar-enterprise" {
+  targets = [
+    "couchbase-columnar-enterprise-1_0_0",
+  ]
+}
+
+group "couchbase-edge-server" {
+  targets = [
+    "couchbase-edge-server-enterprise",
+  ]
+}
+
+group "couchbase-edge-server-enterprise" {
+  targets = [
+    "couchbase-edge-server-enterprise-1_0_0",
+  ]
+}
+
+group "enterprise-analytics" {
+  targets = [
+    "enterprise-analytics-enterprise",
+  ]
+}
+
+group "enterprise-analytics-enterprise" {
+  targets = [
+    "enterprise-analytics-enterprise-2_0_0",
+  ]
+}
+
+target "couchbase-server-community-7_6_2" {
+  context = "community/couchbase-server/7.6.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:community-7.6.2", "couchbase/server:community-7.6", "couchbase/server:community"]
+}
+
+target "couchbase-server-enterprise-4_6_5" {
+  context = "enterprise/couchbase-server/4.6.5"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-4.6.5", "couchbase/server:4.6.5", "couchbase/server:enterprise-4.6", "couchbase/server:4.6"]
+}
+
+target "couchbase-server-enterprise-5_5_0" {
+  context = "enterprise/couchbase-server/5.5.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-5.5.0", "couchbase/server:5.5.0", "couchbase/server:enterprise-5.5", "couchbase/server:5.5"]
+}
+
+target "couchbase-server-enterprise-6_6_0" {
+  context = "enterprise/couchbase-server/6.6.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.6.0", "couchbase/server:6.6.0"]
+}
+
+target "couchbase-server-enterprise-6_6_2" {
+  context = "enterprise/couchbase-server/6.6.2"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-6.6.2", "couchbase/server:6.6.2", "couchbase/server:enterprise-6.6", "couchbase/server:6.6"]
+}
+
+target "couchbase-server-enterprise-7_0_3" {
+  context = "enterprise/couchbase-server/7.0.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server:enterprise-7.0.3", "couchbase/server:7.0.3", "couchbase/server:enterprise-7.0", "couchbase/server:7.0"]
+}
+
+target "couchbase-server-enterprise-7_1_0" {
+  context = "enterprise/couchbase-server/7.1.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.1.0", "couchbase/server:7.1.0", "couchbase/server:enterprise-7.1", "couchbase/server:7.1"]
+}
+
+target "couchbase-server-enterprise-7_2_4" {
+  context = "enterprise/couchbase-server/7.2.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.2.4", "couchbase/server:7.2.4", "couchbase/server:enterprise-7.2", "couchbase/server:7.2"]
+}
+
+target "couchbase-server-enterprise-7_6_1" {
+  context = "enterprise/couchbase-server/7.6.1"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.1", "couchbase/server:7.6.1"]
+}
+
+target "couchbase-server-enterprise-7_6_2" {
+  context = "enterprise/couchbase-server/7.6.2"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-7.6.2", "couchbase/server:7.6.2", "couchbase/server:enterprise-7.6", "couchbase/server:7.6", "couchbase/server:enterprise", "couchbase/server:latest"]
+}
+
+target "couchbase-server-enterprise-8_0_0-staging" {
+  context = "enterprise/couchbase-server/8.0.0-staging"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server:enterprise-8.0.0-staging", "couchbase/server:8.0.0-staging"]
+}
+
+target "sync-gateway-community-1_1_0-forestdb_bucket" {
+  context = "community/sync-gateway/1.1.0-forestdb_bucket"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.1.0-forestdb_bucket"]
+}
+
+target "sync-gateway-community-1_5_0" {
+  context = "community/sync-gateway/1.5.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-1.5.0", "couchbase/sync-gateway:community-1.5", "couchbase/sync-gateway:community"]
+}
+
+target "sync-gateway-community-2_0_0-devbuild" {
+  context = "community/sync-gateway/2.0.0-devbuild"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:community-2.0.0-devbuild"]
+}
+
+target "sync-gateway-enterprise-3_0_3" {
+  context = "enterprise/sync-gateway/3.0.3"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.0.3", "couchbase/sync-gateway:3.0.3"]
+}
+
+target "sync-gateway-enterprise-3_0_4" {
+  context = "enterprise/sync-gateway/3.0.4"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-3.0.4", "couchbase/sync-gateway:3.0.4", "couchbase/sync-gateway:enterprise-3.0", "couchbase/sync-gateway:3.0"]
+}
+
+target "sync-gateway-enterprise-4_0_0" {
+  context = "enterprise/sync-gateway/4.0.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/sync-gateway:enterprise-4.0.0", "couchbase/sync-gateway:4.0.0", "couchbase/sync-gateway:enterprise-4.0", "couchbase/sync-gateway:4.0", "couchbase/sync-gateway:enterprise", "couchbase/sync-gateway:latest"]
+}
+
+target "server-sandbox-enterprise-7_0_5" {
+  context = "enterprise/server-sandbox/7.0.5"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/server-sandbox:7.0.5", "couchbase/server-sandbox:7.0"]
+}
+
+target "server-sandbox-enterprise-7_1_0" {
+  context = "enterprise/server-sandbox/7.1.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/server-sandbox:7.1.0", "couchbase/server-sandbox:7.1", "couchbase/server-sandbox:latest"]
+  contexts = {
+    "couchbase/server:7.1.0" = "target:couchbase-server-enterprise-7_1_0"
+  }
+}
+
+target "couchbase-columnar-enterprise-1_0_0" {
+  context = "enterprise/couchbase-columnar/1.0.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/columnar:1.0.0", "couchbase/columnar:1.0", "couchbase/columnar:latest"]
+}
+
+target "couchbase-edge-server-enterprise-1_0_0" {
+  context = "enterprise/couchbase-edge-server/1.0.0"
+  platforms = ["linux/amd64"]
+  tags = ["couchbase/edge-server:1.0.0", "couchbase/edge-server:1.0", "couchbase/edge-server:latest"]
+}
+
+target "enterprise-analytics-enterprise-2_0_0" {
+  context = "enterprise/enterprise-analytics/2.0.0"
+  platforms = ["linux/amd64", "linux/arm64"]
+  tags = ["couchbase/enterprise-analytics:2.0.0", "couchbase/enterprise-analytics:2.0", "couchbase/enterprise-analytics:latest"]
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ProductVersion is a parsed product version directory name, such as
+// 7.6.2, 7.0.3-MP1, 7.0.0-beta, 1.3.0-274 or 8.0.0-staging
+type ProductVersion struct {
+	// Release is the dotted release number, eg. [7 6 2]
+	Release []int
+	// Prerelease is a label such as "beta", "rc1" or "devbuild" which
+	// sorts before the release itself
+	Prerelease string
+	// Build is a build number, as in 1.3.0-274, or 0 if there is none
+	Build int
+	// MP is the maintenance patch number, as in 7.0.3-MP1, or 0 if there
+	// is none
+	MP int
+	// Staging is set for versions with a -staging suffix, which are
+	// downloaded from the staging release host
+	Staging bool
+}
+
+var (
+	mpPattern         = regexp.MustCompile(`^MP([0-9]+)$`)
+	buildPattern      = regexp.MustCompile(`^[0-9]+$`)
+	prereleasePattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z._]*$`)
+)
+
+// ParseVersion parses a version directory name
+func ParseVersion(s string) (ProductVersion, error) {
+	v := ProductVersion{}
+
+	rest := s
+	if strings.HasSuffix(rest, "-staging") {
+		v.Staging = true
+		rest = strings.TrimSuffix(rest, "-staging")
+	}
+
+	release, suffix, hasSuffix := strings.Cut(rest, "-")
+	for _, section := range strings.Split(release, ".") {
+		n, err := parseVersionNumber(section)
+		if err != nil {
+			return ProductVersion{}, &BadVersionError{Version: s, Err: err}
+		}
+		v.Release = append(v.Release, n)
+	}
+
+	if hasSuffix {
+		switch {
+		case mpPattern.MatchString(suffix):
+			n, err := parseVersionNumber(mpPattern.FindStringSubmatch(suffix)[1])
+			if err != nil || n == 0 {
+				return ProductVersion{}, &BadVersionError{Version: s, Err: fmt.Errorf("bad maintenance patch %q", suffix)}
+			}
+			v.MP = n
+		case buildPattern.MatchString(suffix):
+			n, err := parseVersionNumber(suffix)
+			if err != nil || n == 0 {
+				return ProductVersion{}, &BadVersionError{Version: s, Err: fmt.Errorf("bad build number %q", suffix)}
+			}
+			v.Build = n
+		case prereleasePattern.MatchString(suffix):
+			v.Prerelease = suffix
+		default:
+			return ProductVersion{}, &BadVersionError{Version: s, Err: fmt.Errorf("bad suffix %q", suffix)}
+		}
+	}
+
+	return v, nil
+}
+
+// parseVersionNumber parses one numeric section of a version. Signs and
+// leading zeros are rejected, so every version has a single spelling.
+func parseVersionNumber(s string) (int, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty number")
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("%q is not a number", s)
+		}
+	}
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("%q has a leading zero", s)
+	}
+	return strconv.Atoi(s)
+}
+
+// String returns the version as it was parsed
+func (v ProductVersion) String() string {
+	sections := make([]string, len(v.Release))
+	for i, n := range v.Release {
+		sections[i] = strconv.Itoa(n)
+	}
+	s := strings.Join(sections, ".")
+
+	switch {
+	case v.MP != 0:
+		s += fmt.Sprintf("-MP%d", v.MP)
+	case v.Build != 0:
+		s += fmt.Sprintf("-%d", v.Build)
+	case v.Prerelease != "":
+		s += "-" + v.Prerelease
+	}
+
+	if v.Staging {
+		s += "-staging"
+	}
+	return s
+}
+
+// Suffix returns whatever follows the release number, other than
+// -staging, eg. "MP1", "274" or "beta"
+func (v ProductVersion) Suffix() string {
+	_, suffix, _ := strings.Cut(strings.TrimSuffix(v.String(), "-staging"), "-")
+	return suffix
+}
+
+// compareRelease compares only the release numbers of a and b, treating
+// missing sections as zero, so 7.0 and 7.0.0 are equal
+func compareRelease(a, b []int) int {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		x, y := 0, 0
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return compareInts(x, y)
+		}
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Compare returns -1, 0 or 1 according to whether v sorts before, equal
+// to or after other. Releases are ordered numerically; for the same
+// release, pre-releases come first (in natural order, so rc2 < rc10),
+// then the release itself, then its build numbers and finally its
+// maintenance patches. A staging version sorts just after the same
+// version from the production host.
+func (v ProductVersion) Compare(other ProductVersion) int {
+	if c := compareRelease(v.Release, other.Release); c != 0 {
+		return c
+	}
+	if c := compareInts(v.kind(), other.kind()); c != 0 {
+		return c
+	}
+	switch v.kind() {
+	case 0:
+		if c := compareNatural(v.Prerelease, other.Prerelease); c != 0 {
+			return c
+		}
+	case 2:
+		if c := compareInts(v.Build, other.Build); c != 0 {
+			return c
+		}
+	case 3:
+		if c := compareInts(v.MP, other.MP); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case !v.Staging && other.Staging:
+		return -1
+	case v.Staging && !other.Staging:
+		return 1
+	}
+	return 0
+}
+
+// kind orders the variants of a single release: pre-releases (0), the
+// release (1), builds (2) and maintenance patches (3)
+func (v ProductVersion) kind() int {
+	switch {
+	case v.MP != 0:
+		return 3
+	case v.Build != 0:
+		return 2
+	case v.Prerelease != "":
+		return 0
+	}
+	return 1
+}
+
+// compareNatural compares strings with runs of digits compared
+// numerically, eg. beta < beta2 < beta10
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		aDigits, bDigits := leadingDigits(a), leadingDigits(b)
+		if aDigits != "" && bDigits != "" {
+			aTrimmed := strings.TrimLeft(aDigits, "0")
+			bTrimmed := strings.TrimLeft(bDigits, "0")
+			if len(aTrimmed) != len(bTrimmed) {
+				return compareInts(len(aTrimmed), len(bTrimmed))
+			}
+			if c := strings.Compare(aTrimmed, bTrimmed); c != 0 {
+				return c
+			}
+			if c := compareInts(len(aDigits), len(bDigits)); c != 0 {
+				return c
+			}
+			a, b = a[len(aDigits):], b[len(bDigits):]
+			continue
+		}
+		if a[0] != b[0] {
+			return compareInts(int(a[0]), int(b[0]))
+		}
+		a, b = a[1:], b[1:]
+	}
+	return compareInts(len(a), len(b))
+}
+
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
+
+// VersionConstraint is a comma-separated list of comparisons which must
+// all hold, eg. ">= 6.6.2, <= 7.1.6". Only release numbers are compared,
+// so pre-releases, builds and maintenance patches are subject to the same
+// rules as the release itself (eg. 7.0.3-MP1 satisfies "<= 7.0.3").
+type VersionConstraint []versionComparison
+
+type versionComparison struct {
+	op      string
+	release []int
+}
+
+var constraintPattern = regexp.MustCompile(`^\s*(=|!=|>=|<=|>|<)?\s*([0-9][0-9.]*)\s*$`)
+
+// ParseConstraint parses a VersionConstraint
+func ParseConstraint(s string) (VersionConstraint, error) {
+	constraint := VersionConstraint{}
+	for _, part := range strings.Split(s, ",") {
+		m := constraintPattern.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("bad constraint %q", s)
+		}
+		v, err := ParseVersion(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("bad constraint %q: %v", s, err)
+		}
+		op := m[1]
+		if op == "" {
+			op = "="
+		}
+		constraint = append(constraint, versionComparison{op: op, release: v.Release})
+	}
+	return constraint, nil
+}
+
+// Check returns true if v satisfies every comparison in the constraint
+func (constraint VersionConstraint) Check(v ProductVersion) bool {
+	for _, comparison := range constraint {
+		c := compareRelease(v.Release, comparison.release)
+		var ok bool
+		switch comparison.op {
+		case "=":
+			ok = c == 0
+		case "!=":
+			ok = c != 0
+		case ">":
+			ok = c > 0
+		case ">=":
+			ok = c >= 0
+		case "<":
+			ok = c < 0
+		case "<=":
+			ok = c <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// versionBound is one end of a versionInterval
+type versionBound struct {
+	release   []int
+	inclusive bool
+}
+
+// versionInterval is the range of releases accepted by a constraint. A
+// nil bound is unbounded.
+type versionInterval struct {
+	lower, upper *versionBound
+}
+
+// interval returns the range of releases accepted by the constraint, or an
+// error if it is not a single range (eg. it uses !=)
+func (constraint VersionConstraint) interval() (versionInterval, error) {
+	interval := versionInterval{}
+	for _, comparison := range constraint {
+		var lower, upper *versionBound
+		switch comparison.op {
+		case "=":
+			lower = &versionBound{comparison.release, true}
+			upper = &versionBound{comparison.release, true}
+		case ">":
+			lower = &versionBound{comparison.release, false}
+		case ">=":
+			lower = &versionBound{comparison.release, true}
+		case "<":
+			upper = &versionBound{comparison.release, false}
+		case "<=":
+			upper = &versionBound{comparison.release, true}
+		default:
+			return interval, fmt.Errorf("%s is not a version range", comparison.op)
+		}
+		if lower != nil {
+			if interval.lower != nil {
+				return interval, fmt.Errorf("more than one lower bound")
+			}
+			interval.lower = lower
+		}
+		if upper != nil {
+			if interval.upper != nil {
+				return interval, fmt.Errorf("more than one upper bound")
+			}
+			interval.upper = upper
+		}
+	}
+
+	if interval.lower != nil && interval.upper != nil {
+		c := compareRelease(interval.lower.release, interval.upper.release)
+		if c > 0 || (c == 0 && !(interval.lower.inclusive && interval.upper.inclusive)) {
+			return interval, fmt.Errorf("matches no versions")
+		}
+	}
+	return interval, nil
+}
+
+// adjoins returns true if the interval next starts exactly where this one
+// ends, with no versions in between. Besides ranges meeting at a single
+// version (eg. "< 7.2.0" and ">= 7.2.0"), a range ending at a release
+// adjoins one starting at the next patch release (eg. "<= 7.2.5" and
+// ">= 7.2.6").
+func (interval versionInterval) adjoins(next versionInterval) bool {
+	upper, lower := interval.upper, next.lower
+	if upper == nil || lower == nil {
+		return false
+	}
+	if compareRelease(upper.release, lower.release) == 0 {
+		return upper.inclusive != lower.inclusive
+	}
+	return upper.inclusive && lower.inclusive && isNextPatch(upper.release, lower.release)
+}
+
+// overlaps returns true if some version lies in both intervals, where
+// next does not start before this interval
+func (interval versionInterval) overlaps(next versionInterval) bool {
+	upper, lower := interval.upper, next.lower
+	if upper == nil || lower == nil {
+		return true
+	}
+	c := compareRelease(upper.release, lower.release)
+	return c > 0 || (c == 0 && upper.inclusive && lower.inclusive)
+}
+
+// isNextPatch returns true if b is a with its last section incremented
+func isNextPatch(a, b []int) bool {
+	if len(a) != len(b) || len(a) == 0 {
+		return false
+	}
+	last := len(a) - 1
+	return compareRelease(a[:last], b[:last]) == 0 && b[last] == a[last]+1
+}
+
+// compareLower orders intervals by their lower bounds, unbounded first
+func compareLower(a, b versionInterval) int {
+	switch {
+	case a.lower == nil && b.lower == nil:
+		return 0
+	case a.lower == nil:
+		return -1
+	case b.lower == nil:
+		return 1
+	}
+	if c := compareRelease(a.lower.release, b.lower.release); c != 0 {
+		return c
+	}
+	switch {
+	case a.lower.inclusive && !b.lower.inclusive:
+		return -1
+	case !a.lower.inclusive && b.lower.inclusive:
+		return 1
+	}
+	return 0
+}
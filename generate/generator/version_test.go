@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ProductVersion
+	}{
+		{"7.6.2", ProductVersion{Release: []int{7, 6, 2}}},
+		{"4.0", ProductVersion{Release: []int{4, 0}}},
+		{"100.0.1", ProductVersion{Release: []int{100, 0, 1}}},
+		{"7.0.3-MP1", ProductVersion{Release: []int{7, 0, 3}, MP: 1}},
+		{"7.0.0-beta", ProductVersion{Release: []int{7, 0, 0}, Prerelease: "beta"}},
+		{"1.2.0-rc0", ProductVersion{Release: []int{1, 2, 0}, Prerelease: "rc0"}},
+		{"1.1.0-forestdb_bucket", ProductVersion{Release: []int{1, 1, 0}, Prerelease: "forestdb_bucket"}},
+		{"7.0.0-5017", ProductVersion{Release: []int{7, 0, 0}, Build: 5017}},
+		{"1.3.0-274", ProductVersion{Release: []int{1, 3, 0}, Build: 274}},
+		{"8.0.0-staging", ProductVersion{Release: []int{8, 0, 0}, Staging: true}},
+		{"7.0.3-MP1-staging", ProductVersion{Release: []int{7, 0, 3}, MP: 1, Staging: true}},
+	}
+	for _, test := range tests {
+		got, err := ParseVersion(test.in)
+		if err != nil {
+			t.Errorf("ParseVersion(%q): %v", test.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseVersion(%q) = %+v, want %+v", test.in, got, test.want)
+		}
+		if got.String() != test.in {
+			t.Errorf("ParseVersion(%q).String() = %q", test.in, got.String())
+		}
+	}
+}
+
+func TestParseVersionErrors(t *testing.T) {
+	for _, in := range []string{"", "7..0", "7.0.x", "v7.0.0", "07.0.0", "7.0.0-", "7.0.0-MP0", "7.0.0-0", "7.0.0-a b", "99999999999999999999.0"} {
+		_, err := ParseVersion(in)
+		var badVersion *BadVersionError
+		if !errors.As(err, &badVersion) {
+			t.Errorf("ParseVersion(%q) = %v, want a BadVersionError", in, err)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	// In ascending order
+	versions := []string{
+		"1.2.0-rc0",
+		"1.2.0-rc1",
+		"1.2.0",
+		"1.3.0-274",
+		"6.5.0-beta",
+		"6.5.0-beta2",
+		"6.5.0-beta10",
+		"6.5.0",
+		"7.0",
+		"7.0.0-5017",
+		"7.0.3",
+		"7.0.3-staging",
+		"7.0.3-MP1",
+		"7.0.3-MP2",
+		"7.6.10",
+		"100.0.0",
+	}
+
+	parsed := make([]ProductVersion, len(versions))
+	for i, s := range versions {
+		v, err := ParseVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed[i] = v
+	}
+
+	sorted := []ProductVersion{}
+	for i := len(parsed) - 1; i >= 0; i-- {
+		sorted = append(sorted, parsed[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Compare(sorted[j]) < 0 })
+	for i := range sorted {
+		if sorted[i].String() != versions[i] {
+			t.Errorf("sorted[%d] = %v, want %v", i, sorted[i], versions[i])
+		}
+	}
+
+	if c := parsed[8].Compare(ProductVersion{Release: []int{7, 0, 0}}); c != 0 {
+		t.Errorf("7.0 compared with 7.0.0 = %d, want 0", c)
+	}
+}
+
+func TestVersionConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{">= 7.1.0", "7.1.0", true},
+		{">= 7.1.0", "7.0.5", false},
+		{">= 7.1.0", "7.10.0", true},
+		{"<= 3.0.3", "3.0.3", true},
+		{"<= 3.0.3", "3.0.4", false},
+		{"> 3.0.3", "3.0.4", true},
+		{">= 4.0, < 5.0", "4.6.5", true},
+		{">= 4.0, < 5.0", "5.0.0", false},
+		{"< 7.0.0", "7.0.0-beta", false},
+		{"<= 7.0.3", "7.0.3-MP1", true},
+		{"7.6.2", "7.6.2-staging", true},
+		{"!= 7.6.2", "7.6.2", false},
+	}
+	for _, test := range tests {
+		constraint, err := ParseConstraint(test.constraint)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q): %v", test.constraint, err)
+		}
+		v, err := ParseVersion(test.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := constraint.Check(v); got != test.want {
+			t.Errorf("%q.Check(%q) = %v, want %v", test.constraint, test.version, got, test.want)
+		}
+	}
+
+	for _, bad := range []string{"", "~> 7.0", ">= 7.0.0-beta", ">= 7.0,"} {
+		if _, err := ParseConstraint(bad); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded", bad)
+		}
+	}
+}
+
+func FuzzParseVersion(f *testing.F) {
+	for _, seed := range []string{"7.6.2", "7.0.3-MP1", "7.0.0-beta", "1.3.0-274", "8.0.0-staging", "4.0", "1.1.0-forestdb_bucket"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseVersion(s)
+		if err != nil {
+			return
+		}
+		if v.String() != s {
+			t.Errorf("ParseVersion(%q).String() = %q", s, v.String())
+		}
+		if c := v.Compare(v); c != 0 {
+			t.Errorf("%q compared with itself = %d", s, c)
+		}
+	})
+}
+
+func FuzzCompareVersions(f *testing.F) {
+	f.Add("7.0.3", "7.0.3-MP1")
+	f.Add("6.5.0-beta2", "6.5.0-beta10")
+	f.Add("7.0", "7.0.0-staging")
+	f.Add("1.3.0-274", "1.3.0-rc1")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		va, err := ParseVersion(a)
+		if err != nil {
+			return
+		}
+		vb, err := ParseVersion(b)
+		if err != nil {
+			return
+		}
+		if ab, ba := va.Compare(vb), vb.Compare(va); ab != -ba {
+			t.Errorf("Compare(%q, %q) = %d but Compare(%q, %q) = %d", a, b, ab, b, a, ba)
+		}
+	})
+}